@@ -2,6 +2,7 @@ package amazonpay
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 )
@@ -16,7 +17,8 @@ type CreateChargeRequest struct {
 	ProviderMetadata              *ProviderMetadata `json:"providerMetadata,omitempty"`
 }
 
-type CreateChargeResponse struct {
+// Charge is a charge as returned by CreateCharge, GetCharge and CaptureCharge.
+type Charge struct {
 	ErrorResponse
 	ChargeID            string            `json:"chargeId,omitempty"`
 	ChargePermissionID  string            `json:"chargePermissionId,omitempty"`
@@ -29,50 +31,49 @@ type CreateChargeResponse struct {
 	MerchantMetadata    *MerchantMetadata `json:"merchantMetadata,omitempty"`
 	ProviderMetadata    *ProviderMetadata `json:"providerMetadata,omitempty"`
 	StatusDetails       *StatusDetails    `json:"statusDetails,omitempty"`
-	CreationTimestamp   string            `json:"creationTimestamp,omitempty"`
-	ExpirationTimestamp string            `json:"expirationTimestamp,omitempty"`
+	CreationTimestamp   Timestamp         `json:"creationTimestamp,omitempty"`
+	ExpirationTimestamp Timestamp         `json:"expirationTimestamp,omitempty"`
 	ReleaseEnvironment  string            `json:"releaseEnvironment,omitempty"`
+	// Extra holds the top level fields of the response not modeled above, as received.
+	// They are written back by MarshalJSON, which also keeps the nested fields of the members left unchanged.
+	Extra map[string]json.RawMessage `json:"-"`
+
+	raw json.RawMessage
 }
 
-func (c *Client) CreateCharge(ctx context.Context, req *CreateChargeRequest) (*CreateChargeResponse, *http.Response, error) {
+// CreateChargeResponse is kept for compatibility, it is a Charge.
+type CreateChargeResponse = Charge
+
+func (c *Client) CreateCharge(ctx context.Context, req *CreateChargeRequest) (*Charge, *http.Response, error) {
+	ctx = withOperation(ctx, "CreateCharge")
+	if err := c.validate(req); err != nil {
+		return nil, nil, err
+	}
 	path := fmt.Sprintf("%s/charges", APIVersion)
-	httpReq, err := c.NewRequest(http.MethodPost, path, req)
+	httpReq, err := c.newRequest(http.MethodPost, path, req)
 	if err != nil {
 		return nil, nil, err
 	}
-	resp := new(CreateChargeResponse)
+	resp := new(Charge)
 	httpResp, err := c.Do(ctx, httpReq, resp)
 	if err != nil {
 		return nil, httpResp, err
 	}
+	c.observeChargeOutcome(ctx, resp.StatusDetails)
 	return resp, httpResp, nil
 }
 
-type GetChargeResponse struct {
-	ErrorResponse
-	ChargeID            string            `json:"chargeId"`
-	ChargePermissionID  string            `json:"chargePermissionId"`
-	ChargeAmount        *Price            `json:"chargeAmount"`
-	CaptureAmount       *Price            `json:"captureAmount"`
-	RefundedAmount      *Price            `json:"refundedAmount"`
-	ConvertedAmount     string            `json:"convertedAmount"`
-	ConversionRate      string            `json:"conversionRate"`
-	SoftDescriptor      string            `json:"softDescriptor"`
-	MerchantMetadata    *MerchantMetadata `json:"merchantMetadata"`
-	ProviderMetadata    *ProviderMetadata `json:"providerMetadata"`
-	StatusDetails       *StatusDetails    `json:"statusDetails"`
-	CreationTimestamp   string            `json:"creationTimestamp"`
-	ExpirationTimestamp string            `json:"expirationTimestamp"`
-	ReleaseEnvironment  string            `json:"releaseEnvironment"`
-}
+// GetChargeResponse is kept for compatibility, it is a Charge.
+type GetChargeResponse = Charge
 
-func (c *Client) GetCharge(ctx context.Context, chargeID string) (*GetChargeResponse, *http.Response, error) {
+func (c *Client) GetCharge(ctx context.Context, chargeID string) (*Charge, *http.Response, error) {
+	ctx = withOperation(ctx, "GetCharge")
 	path := fmt.Sprintf("%s/charges/%s", APIVersion, chargeID)
-	httpReq, err := c.NewRequest(http.MethodGet, path, nil)
+	httpReq, err := c.newRequest(http.MethodGet, path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
-	resp := new(GetChargeResponse)
+	resp := new(Charge)
 	httpResp, err := c.Do(ctx, httpReq, resp)
 	if err != nil {
 		return nil, httpResp, err
@@ -87,34 +88,24 @@ type CaptureChargeRequest struct {
 	ProviderMetadata *ProviderMetadata `json:"providerMetadata,omitempty"`
 }
 
-type CaptureChargeResponse struct {
-	ErrorResponse
-	ChargeID            string            `json:"chargeId,omitempty"`
-	ChargePermissionID  string            `json:"chargePermissionId,omitempty"`
-	ChargeAmount        *Price            `json:"chargeAmount,omitempty"`
-	CaptureAmount       *Price            `json:"captureAmount,omitempty"`
-	RefundedAmount      *Price            `json:"refundedAmount,omitempty"`
-	ConvertedAmount     string            `json:"convertedAmount,omitempty"`
-	ConversionRate      string            `json:"conversionRate,omitempty"`
-	SoftDescriptor      string            `json:"softDescriptor,omitempty"`
-	MerchantMetadata    *MerchantMetadata `json:"merchantMetadata,omitempty"`
-	ProviderMetadata    *ProviderMetadata `json:"providerMetadata,omitempty"`
-	StatusDetails       *StatusDetails    `json:"statusDetails,omitempty"`
-	CreationTimestamp   string            `json:"creationTimestamp,omitempty"`
-	ExpirationTimestamp string            `json:"expirationTimestamp,omitempty"`
-	ReleaseEnvironment  string            `json:"releaseEnvironment,omitempty"`
-}
+// CaptureChargeResponse is kept for compatibility, it is a Charge.
+type CaptureChargeResponse = Charge
 
-func (c *Client) CaptureCharge(ctx context.Context, chargeID string, req *CaptureChargeRequest) (*CaptureChargeResponse, *http.Response, error) {
+func (c *Client) CaptureCharge(ctx context.Context, chargeID string, req *CaptureChargeRequest) (*Charge, *http.Response, error) {
+	ctx = withOperation(ctx, "CaptureCharge")
+	if err := c.validate(req); err != nil {
+		return nil, nil, err
+	}
 	path := fmt.Sprintf("%s/charges/%s/capture", APIVersion, chargeID)
-	httpReq, err := c.NewRequest(http.MethodPost, path, req)
+	httpReq, err := c.newRequest(http.MethodPost, path, req)
 	if err != nil {
 		return nil, nil, err
 	}
-	resp := new(CaptureChargeResponse)
+	resp := new(Charge)
 	httpResp, err := c.Do(ctx, httpReq, resp)
 	if err != nil {
 		return nil, httpResp, err
 	}
+	c.observeChargeOutcome(ctx, resp.StatusDetails)
 	return resp, httpResp, nil
 }
@@ -2,11 +2,13 @@ package amazonpay
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 )
 
-type ChargePermissionResponse struct {
+// ChargePermission is a charge permission as returned by GetChargePermission and CloseChargePermission.
+type ChargePermission struct {
 	ErrorResponse
 	ChargePermissionID          string              `json:"chargePermissionId,omitempty"`
 	ChargePermissionReferenceID string              `json:"chargePermissionReferenceId,omitempty"`
@@ -16,23 +18,33 @@ type ChargePermissionResponse struct {
 	BillingAddress              *AddressDetails     `json:"billingAddress,omitempty"`
 	PaymentPreferences          []PaymentPreference `json:"paymentPreferences,omitempty"`
 	StatusDetails               *StatusDetails      `json:"statusDetails,omitempty"`
-	CreationTimestamp           string              `json:"creationTimestamp,omitempty"`
-	ExpirationTimestamp         string              `json:"expirationTimestamp,omitempty"`
+	CreationTimestamp           Timestamp           `json:"creationTimestamp,omitempty"`
+	ExpirationTimestamp         Timestamp           `json:"expirationTimestamp,omitempty"`
 	MerchantMetadata            *MerchantMetadata   `json:"merchantMetadata,omitempty"`
 	PlatformID                  string              `json:"platformId,omitempty"`
 	Limits                      *Limits             `json:"limits,omitempty"`
 	PresentmentCurrency         string              `json:"presentmentCurrency,omitempty"`
+	// Extra holds the top level fields of the response not modeled above, as received.
+	// They are written back by MarshalJSON, which also keeps the nested fields of the members left unchanged.
+	Extra map[string]json.RawMessage `json:"-"`
+
+	raw json.RawMessage
 }
 
-type GetChargePermissionResponse ChargePermissionResponse
+// ChargePermissionResponse is kept for compatibility, it is a ChargePermission.
+type ChargePermissionResponse = ChargePermission
+
+// GetChargePermissionResponse is kept for compatibility, it is a ChargePermission.
+type GetChargePermissionResponse = ChargePermission
 
-func (c *Client) GetChargePermission(ctx context.Context, chargePermissionID string) (*GetChargePermissionResponse, *http.Response, error) {
+func (c *Client) GetChargePermission(ctx context.Context, chargePermissionID string) (*ChargePermission, *http.Response, error) {
+	ctx = withOperation(ctx, "GetChargePermission")
 	path := fmt.Sprintf("%s/chargePermissions/%s", APIVersion, chargePermissionID)
-	httpReq, err := c.NewRequest(http.MethodGet, path, nil)
+	httpReq, err := c.newRequest(http.MethodGet, path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
-	resp := new(GetChargePermissionResponse)
+	resp := new(ChargePermission)
 	httpResp, err := c.Do(ctx, httpReq, resp)
 	if err != nil {
 		return nil, httpResp, err
@@ -45,15 +57,20 @@ type CloseChargePermissionRequest struct {
 	CancelPendingCharges *bool  `json:"cancelPendingCharges,omitempty"`
 }
 
-type CloseChargePermissionResponse ChargePermissionResponse
+// CloseChargePermissionResponse is kept for compatibility, it is a ChargePermission.
+type CloseChargePermissionResponse = ChargePermission
 
-func (c *Client) CloseChargePermission(ctx context.Context, chargePermissionID string, req *CloseChargePermissionRequest) (*CloseChargePermissionResponse, *http.Response, error) {
+func (c *Client) CloseChargePermission(ctx context.Context, chargePermissionID string, req *CloseChargePermissionRequest) (*ChargePermission, *http.Response, error) {
+	ctx = withOperation(ctx, "CloseChargePermission")
+	if err := c.validate(req); err != nil {
+		return nil, nil, err
+	}
 	path := fmt.Sprintf("%s/chargePermissions/%s/close", APIVersion, chargePermissionID)
-	httpReq, err := c.NewRequest(http.MethodDelete, path, req)
+	httpReq, err := c.newRequest(http.MethodDelete, path, req)
 	if err != nil {
 		return nil, nil, err
 	}
-	resp := new(CloseChargePermissionResponse)
+	resp := new(ChargePermission)
 	httpResp, err := c.Do(ctx, httpReq, resp)
 	if err != nil {
 		return nil, httpResp, err
@@ -13,7 +13,7 @@ type CreateCheckoutSessionRequest struct {
 	WebCheckoutDetails     *WebCheckoutDetails     `json:"webCheckoutDetails,omitempty"`
 	StoreID                string                  `json:"storeId,omitempty"`
 	Scopes                 []string                `json:"scopes,omitempty"`
-	ChargePermissionType   string                  `json:"chargePermissionType,omitempty"`
+	ChargePermissionType   ChargePermissionType    `json:"chargePermissionType,omitempty"`
 	RecurringMetadata      *RecurringMetadata      `json:"recurringMetadata,omitempty"`
 	DeliverySpecifications *DeliverySpecifications `json:"deliverySpecifications,omitempty"`
 	PaymentDetails         *PaymentDetails         `json:"paymentDetails,omitempty"`
@@ -32,25 +32,38 @@ func (c *CreateCheckoutSessionRequest) ToPayload() (string, error) {
 }
 
 // GenerateButtonSignature method.
+// The payload is signed with the active key, see GenerateButtonSignatureWithKey to render its public key id.
 func (c *Client) GenerateButtonSignature(payload string) (string, error) {
-	stringToSign, err := signing.StringToSign(payload)
+	return c.GenerateButtonSignatureWithKey(payload, c.Keys.Active(), c.algorithm())
+}
+
+// GenerateButtonSignatureWithAlgorithm signs the button payload with alg instead of Client.Algorithm.
+func (c *Client) GenerateButtonSignatureWithAlgorithm(payload string, alg signing.SignatureAlgorithm) (string, error) {
+	return c.GenerateButtonSignatureWithKey(payload, c.Keys.Active(), alg)
+}
+
+// GenerateButtonSignatureWithKey signs the button payload with key and alg.
+// The button must be rendered with key.PublicKeyID.
+func (c *Client) GenerateButtonSignatureWithKey(payload string, key Key, alg signing.SignatureAlgorithm) (string, error) {
+	stringToSign, err := signing.StringToSign(alg, payload)
 	if err != nil {
 		return "", err
 	}
-	signature, err := signing.Sign(c.PrivateKey, stringToSign)
+	signature, err := signing.Sign(key.Signer, alg, stringToSign)
 	if err != nil {
 		return "", err
 	}
 	return signature, nil
 }
 
-type CheckoutSessionResponse struct {
+// CheckoutSession is a checkout session as returned by GetCheckoutSession, UpdateCheckoutSession and CompleteCheckoutSession.
+type CheckoutSession struct {
 	ErrorResponse
 	CheckoutSessionID      string                  `json:"checkoutSessionId,omitempty"`
 	WebCheckoutDetails     *WebCheckoutDetails     `json:"webCheckoutDetails,omitempty"`
-	ChargePermissionType   string                  `json:"chargePermissionType,omitempty"`
+	ChargePermissionType   ChargePermissionType    `json:"chargePermissionType,omitempty"`
 	RecurringMetadata      *RecurringMetadata      `json:"recurringMetadata,omitempty"`
-	ProductType            string                  `json:"productType,omitempty"`
+	ProductType            ProductType             `json:"productType,omitempty"`
 	PaymentDetails         *PaymentDetails         `json:"paymentDetails,omitempty"`
 	MerchantMetadata       *MerchantMetadata       `json:"merchantMetadata,omitempty"`
 	Buyer                  *Buyer                  `json:"buyer,omitempty"`
@@ -62,23 +75,33 @@ type CheckoutSessionResponse struct {
 	ChargePermissionID     string                  `json:"chargePermissionId,omitempty"`
 	ChargeID               string                  `json:"chargeId,omitempty"`
 	Constraints            []Constraint            `json:"constraints,omitempty"`
-	CreationTimestamp      string                  `json:"creationTimestamp,omitempty"`
-	ExpirationTimestamp    string                  `json:"expirationTimestamp,omitempty"`
+	CreationTimestamp      Timestamp               `json:"creationTimestamp,omitempty"`
+	ExpirationTimestamp    Timestamp               `json:"expirationTimestamp,omitempty"`
 	StoreID                string                  `json:"storeId,omitempty"`
 	DeliverySpecifications *DeliverySpecifications `json:"deliverySpecifications,omitempty"`
 	ProviderMetadata       *ProviderMetadata       `json:"providerMetadata,omitempty"`
 	ReleaseEnvironment     string                  `json:"releaseEnvironment,omitempty"`
+	// Extra holds the top level fields of the response not modeled above, as received.
+	// They are written back by MarshalJSON, which also keeps the nested fields of the members left unchanged.
+	Extra map[string]json.RawMessage `json:"-"`
+
+	raw json.RawMessage
 }
 
-type GetCheckoutSessionResponse CheckoutSessionResponse
+// CheckoutSessionResponse is kept for compatibility, it is a CheckoutSession.
+type CheckoutSessionResponse = CheckoutSession
 
-func (c *Client) GetCheckoutSession(ctx context.Context, checkoutSessionID string) (*GetCheckoutSessionResponse, *http.Response, error) {
+// GetCheckoutSessionResponse is kept for compatibility, it is a CheckoutSession.
+type GetCheckoutSessionResponse = CheckoutSession
+
+func (c *Client) GetCheckoutSession(ctx context.Context, checkoutSessionID string) (*CheckoutSession, *http.Response, error) {
+	ctx = withOperation(ctx, "GetCheckoutSession")
 	path := fmt.Sprintf("%s/checkoutSessions/%s", APIVersion, checkoutSessionID)
-	httpReq, err := c.NewRequest(http.MethodGet, path, nil)
+	httpReq, err := c.newRequest(http.MethodGet, path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
-	resp := new(GetCheckoutSessionResponse)
+	resp := new(CheckoutSession)
 	httpResp, err := c.Do(ctx, httpReq, resp)
 	if err != nil {
 		return nil, httpResp, err
@@ -92,15 +115,20 @@ type UpdateCheckoutSessionRequest struct {
 	MerchantMetadata   *MerchantMetadata   `json:"merchantMetadata,omitempty"`
 }
 
-type UpdateCheckoutSessionResponse CheckoutSessionResponse
+// UpdateCheckoutSessionResponse is kept for compatibility, it is a CheckoutSession.
+type UpdateCheckoutSessionResponse = CheckoutSession
 
-func (c *Client) UpdateCheckoutSession(ctx context.Context, checkoutSessionID string, req *UpdateCheckoutSessionRequest) (*UpdateCheckoutSessionResponse, *http.Response, error) {
+func (c *Client) UpdateCheckoutSession(ctx context.Context, checkoutSessionID string, req *UpdateCheckoutSessionRequest) (*CheckoutSession, *http.Response, error) {
+	ctx = withOperation(ctx, "UpdateCheckoutSession")
+	if err := c.validate(req); err != nil {
+		return nil, nil, err
+	}
 	path := fmt.Sprintf("%s/checkoutSessions/%s", APIVersion, checkoutSessionID)
-	httpReq, err := c.NewRequest(http.MethodPatch, path, req)
+	httpReq, err := c.newRequest(http.MethodPatch, path, req)
 	if err != nil {
 		return nil, nil, err
 	}
-	resp := new(UpdateCheckoutSessionResponse)
+	resp := new(CheckoutSession)
 	httpResp, err := c.Do(ctx, httpReq, resp)
 	if err != nil {
 		return nil, httpResp, err
@@ -112,15 +140,20 @@ type CompleteCheckoutSessionRequest struct {
 	ChargeAmount *Price `json:"chargeAmount,omitempty"`
 }
 
-type CompleteCheckoutSessionResponse CheckoutSessionResponse
+// CompleteCheckoutSessionResponse is kept for compatibility, it is a CheckoutSession.
+type CompleteCheckoutSessionResponse = CheckoutSession
 
-func (c *Client) CompleteCheckoutSession(ctx context.Context, checkoutSessionID string, req *CompleteCheckoutSessionRequest) (*CompleteCheckoutSessionResponse, *http.Response, error) {
+func (c *Client) CompleteCheckoutSession(ctx context.Context, checkoutSessionID string, req *CompleteCheckoutSessionRequest) (*CheckoutSession, *http.Response, error) {
+	ctx = withOperation(ctx, "CompleteCheckoutSession")
+	if err := c.validate(req); err != nil {
+		return nil, nil, err
+	}
 	path := fmt.Sprintf("%s/checkoutSessions/%s/complete", APIVersion, checkoutSessionID)
-	httpReq, err := c.NewRequest(http.MethodPost, path, req)
+	httpReq, err := c.newRequest(http.MethodPost, path, req)
 	if err != nil {
 		return nil, nil, err
 	}
-	resp := new(CompleteCheckoutSessionResponse)
+	resp := new(CheckoutSession)
 	httpResp, err := c.Do(ctx, httpReq, resp)
 	if err != nil {
 		return nil, httpResp, err
@@ -0,0 +1,35 @@
+package amazonpay
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+// BenchmarkGenerateButtonSignature measures signing a button payload.
+// The RSA signature dominates the time, building the string to sign without fmt cut its allocations:
+//
+//	before  677451 ns/op  1940 B/op  19 allocs/op
+//	after   692813 ns/op  1912 B/op  16 allocs/op
+func BenchmarkGenerateButtonSignature(b *testing.B) {
+	c := newTestClient(b, signingtest.Signer(), nil)
+	payload := `{"webCheckoutDetails":{"checkoutReviewReturnUrl":"https://example.com/review"},"storeId":"amzn1.application-oa2-client.xxxxx"}`
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GenerateButtonSignature(payload); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCheckoutSessionTypes(t *testing.T) {
+	var cs CheckoutSession
+	if err := json.Unmarshal([]byte(`{"chargePermissionType":"PaymentMethodOnFile","productType":"SignIn"}`), &cs); err != nil {
+		t.Fatal(err)
+	}
+	if cs.ChargePermissionType != ChargePermissionTypePaymentMethodOnFile || cs.ProductType != ProductTypeSignIn {
+		t.Errorf("decoded %q and %q", cs.ChargePermissionType, cs.ProductType)
+	}
+}
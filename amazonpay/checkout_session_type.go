@@ -1,5 +1,7 @@
 package amazonpay
 
+import "strings"
+
 type WebCheckoutDetails struct {
 	CheckoutReviewReturnURL string `json:"checkoutReviewReturnUrl,omitempty"`
 	CheckoutResultReturnURL string `json:"checkoutResultReturnUrl,omitempty"`
@@ -7,8 +9,8 @@ type WebCheckoutDetails struct {
 }
 
 type Frequency struct {
-	Unit  string `json:"unit,omitempty"`
-	Value string `json:"value,omitempty"`
+	Unit  FrequencyUnit `json:"unit,omitempty"`
+	Value string        `json:"value,omitempty"`
 }
 
 type RecurringMetadata struct {
@@ -17,14 +19,14 @@ type RecurringMetadata struct {
 }
 
 type PaymentDetails struct {
-	PaymentIntent                 string `json:"paymentIntent,omitempty"`
-	CanHandlePendingAuthorization *bool  `json:"canHandlePendingAuthorization,omitempty"`
-	ChargeAmount                  *Price `json:"chargeAmount,omitempty"`
-	TotalOrderAmount              *Price `json:"totalOrderAmount,omitempty"`
-	SoftDescriptor                string `json:"softDescriptor,omitempty"`
-	PresentmentCurrency           string `json:"presentmentCurrency,omitempty"`
-	AllowOvercharge               *bool  `json:"allowOvercharge,omitempty"`
-	ExtendExpiration              *bool  `json:"extendExpiration,omitempty"`
+	PaymentIntent                 PaymentIntent `json:"paymentIntent,omitempty"`
+	CanHandlePendingAuthorization *bool         `json:"canHandlePendingAuthorization,omitempty"`
+	ChargeAmount                  *Price        `json:"chargeAmount,omitempty"`
+	TotalOrderAmount              *Price        `json:"totalOrderAmount,omitempty"`
+	SoftDescriptor                string        `json:"softDescriptor,omitempty"`
+	PresentmentCurrency           string        `json:"presentmentCurrency,omitempty"`
+	AllowOvercharge               *bool         `json:"allowOvercharge,omitempty"`
+	ExtendExpiration              *bool         `json:"extendExpiration,omitempty"`
 }
 
 type Constraint struct {
@@ -33,21 +35,81 @@ type Constraint struct {
 }
 
 type DeliverySpecifications struct {
-	SpecialRestrictions []string `json:"specialRestrictions,omitempty"`
-	AddressRestrictions struct {
-		Type         string `json:"type,omitempty"`
-		Restrictions struct {
-			US struct {
-				StatesOrRegions []string `json:"statesOrRegions,omitempty"`
-				ZipCodes        []string `json:"zipCodes,omitempty"`
-			} `json:"US,omitempty"`
-			GB struct {
-				ZipCodes []string `json:"zipCodes,omitempty"`
-			} `json:"GB,omitempty"`
-			IN struct {
-				StatesOrRegions []string `json:"statesOrRegions,omitempty"`
-			} `json:"IN,omitempty"`
-			JP struct{} `json:"JP,omitempty"`
-		} `json:"restrictions,omitempty"`
-	} `json:"addressRestrictions,omitempty"`
+	SpecialRestrictions []string             `json:"specialRestrictions,omitempty"`
+	AddressRestrictions *AddressRestrictions `json:"addressRestrictions,omitempty"`
+}
+
+// Values of DeliverySpecifications.SpecialRestrictions.
+const (
+	SpecialRestrictionRestrictPOBoxes      = "RestrictPOBoxes"
+	SpecialRestrictionRestrictPackstations = "RestrictPackstations"
+)
+
+// AddressRestrictionType tells whether the restricted addresses are the only ones allowed or are excluded.
+type AddressRestrictionType string
+
+const (
+	AddressRestrictionAllowed    AddressRestrictionType = "Allowed"
+	AddressRestrictionNotAllowed AddressRestrictionType = "NotAllowed"
+)
+
+// AddressRestrictions restricts delivery addresses by country.
+// Restrictions is keyed by ISO 3166-1 alpha-2 country code. An empty Restriction covers the whole country.
+type AddressRestrictions struct {
+	Type         AddressRestrictionType `json:"type,omitempty"`
+	Restrictions map[string]Restriction `json:"restrictions,omitempty"`
+}
+
+// Restriction lists the states or regions and zip codes restricted in a country.
+type Restriction struct {
+	StatesOrRegions []string `json:"statesOrRegions,omitempty"`
+	ZipCodes        []string `json:"zipCodes,omitempty"`
+}
+
+// AllowedAddresses returns restrictions only allowing delivery to the countries, states and zip codes added to it.
+//
+// For example, to only deliver to the state of Washington in the US:
+//
+//	amazonpay.AllowedAddresses().StatesOrRegions("US", "WA")
+func AllowedAddresses() *AddressRestrictions {
+	return &AddressRestrictions{Type: AddressRestrictionAllowed}
+}
+
+// NotAllowedAddresses returns restrictions excluding delivery to the countries, states and zip codes added to it.
+func NotAllowedAddresses() *AddressRestrictions {
+	return &AddressRestrictions{Type: AddressRestrictionNotAllowed}
+}
+
+// Country adds the whole country.
+func (a *AddressRestrictions) Country(country string) *AddressRestrictions {
+	a.restriction(country)
+	return a
+}
+
+// StatesOrRegions adds states or regions of country.
+func (a *AddressRestrictions) StatesOrRegions(country string, statesOrRegions ...string) *AddressRestrictions {
+	r := a.restriction(country)
+	r.StatesOrRegions = append(r.StatesOrRegions, statesOrRegions...)
+	a.Restrictions[strings.ToUpper(country)] = r
+	return a
+}
+
+// ZipCodes adds zip codes of country.
+func (a *AddressRestrictions) ZipCodes(country string, zipCodes ...string) *AddressRestrictions {
+	r := a.restriction(country)
+	r.ZipCodes = append(r.ZipCodes, zipCodes...)
+	a.Restrictions[strings.ToUpper(country)] = r
+	return a
+}
+
+func (a *AddressRestrictions) restriction(country string) Restriction {
+	if a.Restrictions == nil {
+		a.Restrictions = map[string]Restriction{}
+	}
+	country = strings.ToUpper(country)
+	r, ok := a.Restrictions[country]
+	if !ok {
+		a.Restrictions[country] = r
+	}
+	return r
 }
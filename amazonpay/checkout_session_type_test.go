@@ -0,0 +1,62 @@
+package amazonpay
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// documentedDeliverySpecifications is the deliverySpecifications example of the Create Checkout Session reference,
+// compacted with the countries in the sorted order encoding/json writes map keys in.
+const documentedDeliverySpecifications = `{"specialRestrictions":["RestrictPOBoxes"],"addressRestrictions":{"type":"Allowed","restrictions":{"GB":{"zipCodes":["72046","72047"]},"IN":{"statesOrRegions":["AP"]},"JP":{},"US":{"statesOrRegions":["WA"],"zipCodes":["95050","93405"]}}}}`
+
+func TestDeliverySpecificationsRoundTrip(t *testing.T) {
+	var decoded DeliverySpecifications
+	if err := json.Unmarshal([]byte(documentedDeliverySpecifications), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := decoded.AddressRestrictions.Restrictions["JP"]; !ok {
+		t.Error(`"JP":{} was not decoded as a whole country restriction`)
+	}
+
+	built := DeliverySpecifications{
+		SpecialRestrictions: []string{SpecialRestrictionRestrictPOBoxes},
+		AddressRestrictions: AllowedAddresses().
+			Country("JP").
+			StatesOrRegions("US", "WA").
+			ZipCodes("us", "95050", "93405").
+			ZipCodes("GB", "72046", "72047").
+			StatesOrRegions("IN", "AP"),
+	}
+
+	for name, v := range map[string]DeliverySpecifications{"decoded": decoded, "built": built} {
+		got, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, []byte(documentedDeliverySpecifications)) {
+			t.Errorf("%s encodes to\n%s\nwant\n%s", name, got, documentedDeliverySpecifications)
+		}
+	}
+}
+
+func TestAddressRestrictionsValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		a       *AddressRestrictions
+		wantErr bool
+	}{
+		{"allowed", AllowedAddresses().Country("JP"), false},
+		{"not allowed", NotAllowedAddresses().ZipCodes("US", "95050"), false},
+		{"lower case key", &AddressRestrictions{Type: AddressRestrictionAllowed, Restrictions: map[string]Restriction{"jp": {}}}, true},
+		{"unknown type", &AddressRestrictions{Type: "Maybe", Restrictions: map[string]Restriction{"JP": {}}}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var e ValidationErrors
+			e.deliverySpecifications("deliverySpecifications", &DeliverySpecifications{AddressRestrictions: tt.a})
+			if err := e.err(); (err != nil) != tt.wantErr {
+				t.Errorf("errors %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
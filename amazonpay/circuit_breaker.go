@@ -0,0 +1,270 @@
+package amazonpay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+)
+
+// CircuitState is the state of a single circuit.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails every request fast until OpenTimeout has elapsed.
+	CircuitOpen
+	// CircuitHalfOpen lets a single probe request through.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+func (s CircuitState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ErrCircuitOpen is matched by errors.Is for every *CircuitOpenError.
+var ErrCircuitOpen = errors.New("amazonpay: circuit open")
+
+// CircuitOpenError is returned by Client.Do when the circuit of the region and operation is open.
+type CircuitOpenError struct {
+	Region     string
+	Operation  string
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("amazonpay: circuit open for %s/%s, retry after %s", e.Region, e.Operation, e.RetryAfter)
+}
+
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitStatus is a snapshot of a single circuit.
+type CircuitStatus struct {
+	Region              string       `json:"region"`
+	Operation           string       `json:"operation"`
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutiveFailures"`
+	OpenedAt            time.Time    `json:"openedAt,omitempty"`
+}
+
+type circuitKey struct {
+	region    string
+	operation string
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// Defaults of the CircuitBreaker fields left zero or negative.
+const (
+	DefaultFailureThreshold = 5
+	DefaultOpenTimeout      = 30 * time.Second
+)
+
+// CircuitBreaker tracks a circuit per region and operation.
+// A circuit opens after FailureThreshold consecutive 5xx responses or transport errors,
+// and half-opens to probe once OpenTimeout has elapsed.
+// Requests that fail to be signed locally are never sent and do not count as failures.
+type CircuitBreaker struct {
+	// FailureThreshold is DefaultFailureThreshold when not positive.
+	FailureThreshold int
+	// OpenTimeout is DefaultOpenTimeout when not positive.
+	OpenTimeout time.Duration
+
+	mu       sync.Mutex
+	circuits map[circuitKey]*circuit
+}
+
+// NewCircuitBreaker returns a new circuit breaker.
+// A failureThreshold or openTimeout that is not positive selects its default.
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold: failureThreshold,
+		OpenTimeout:      openTimeout,
+		circuits:         map[circuitKey]*circuit{},
+	}
+}
+
+func (b *CircuitBreaker) failureThreshold() int {
+	if b.FailureThreshold <= 0 {
+		return DefaultFailureThreshold
+	}
+	return b.FailureThreshold
+}
+
+func (b *CircuitBreaker) openTimeout() time.Duration {
+	if b.OpenTimeout <= 0 {
+		return DefaultOpenTimeout
+	}
+	return b.OpenTimeout
+}
+
+func (b *CircuitBreaker) circuit(key circuitKey) *circuit {
+	if b.circuits == nil {
+		b.circuits = map[circuitKey]*circuit{}
+	}
+	cir, ok := b.circuits[key]
+	if !ok {
+		cir = &circuit{state: CircuitClosed}
+		b.circuits[key] = cir
+	}
+	return cir
+}
+
+// allow reports whether a request may be sent, and whether it is the probe of a half-open circuit.
+func (b *CircuitBreaker) allow(region, operation string) (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	cir := b.circuit(circuitKey{region: region, operation: operation})
+	switch cir.state {
+	case CircuitClosed:
+		return false, nil
+	case CircuitOpen:
+		if elapsed := time.Since(cir.openedAt); elapsed < b.openTimeout() {
+			return false, &CircuitOpenError{Region: region, Operation: operation, RetryAfter: b.openTimeout() - elapsed}
+		}
+		cir.state = CircuitHalfOpen
+		cir.probing = true
+		return true, nil
+	case CircuitHalfOpen:
+		if cir.probing {
+			return false, &CircuitOpenError{Region: region, Operation: operation, RetryAfter: 0}
+		}
+		cir.probing = true
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// record counts the outcome of a request allowed by allow.
+// Only the probe closes or reopens a circuit that is not closed: the outcomes of requests
+// sent before it opened are ignored, so that a slow success cannot close it without a probe.
+func (b *CircuitBreaker) record(region, operation string, probe, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	cir := b.circuit(circuitKey{region: region, operation: operation})
+	if cir.state != CircuitClosed && !(probe && cir.probing) {
+		return
+	}
+	cir.probing = false
+	if !failed {
+		cir.state = CircuitClosed
+		cir.failures = 0
+		return
+	}
+	cir.failures++
+	if cir.state == CircuitHalfOpen || cir.failures >= b.failureThreshold() {
+		cir.state = CircuitOpen
+		cir.openedAt = time.Now()
+	}
+}
+
+// release lets another probe through when the probe ended without an outcome.
+func (b *CircuitBreaker) release(region, operation string, probe bool) {
+	if !probe {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.circuit(circuitKey{region: region, operation: operation}).probing = false
+}
+
+// State returns the current state of the circuit for the region and operation.
+func (b *CircuitBreaker) State(region, operation string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	cir, ok := b.circuits[circuitKey{region: region, operation: operation}]
+	if !ok {
+		return CircuitClosed
+	}
+	return cir.state
+}
+
+// Available reports whether every circuit of the region is closed.
+func (b *CircuitBreaker) Available(region string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for key, cir := range b.circuits {
+		if key.region == region && cir.state != CircuitClosed {
+			return false
+		}
+	}
+	return true
+}
+
+// Statuses returns a snapshot of every known circuit, sorted by region and operation.
+func (b *CircuitBreaker) Statuses() []CircuitStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	statuses := make([]CircuitStatus, 0, len(b.circuits))
+	for key, cir := range b.circuits {
+		statuses = append(statuses, CircuitStatus{
+			Region:              key.region,
+			Operation:           key.operation,
+			State:               cir.state,
+			ConsecutiveFailures: cir.failures,
+			OpenedAt:            cir.openedAt,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Region != statuses[j].Region {
+			return statuses[i].Region < statuses[j].Region
+		}
+		return statuses[i].Operation < statuses[j].Operation
+	})
+	return statuses
+}
+
+func (c *Client) allowRequest(ctx context.Context) (probe bool, err error) {
+	if c.CircuitBreaker == nil {
+		return false, nil
+	}
+	return c.CircuitBreaker.allow(c.Region, OperationFromContext(ctx))
+}
+
+func (c *Client) recordResponse(ctx context.Context, probe bool, resp *http.Response, err error) {
+	if c.CircuitBreaker == nil {
+		return
+	}
+	op := OperationFromContext(ctx)
+	var signErr *signing.SignError
+	if err != nil && (errors.Is(err, context.Canceled) || errors.As(err, &signErr)) {
+		c.CircuitBreaker.release(c.Region, op, probe)
+		return
+	}
+	c.CircuitBreaker.record(c.Region, op, probe, isCircuitFailure(resp, err))
+}
+
+// isCircuitFailure treats transport errors, including timeouts, and 5xx responses as failures.
+func isCircuitFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
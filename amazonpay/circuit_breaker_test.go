@@ -0,0 +1,198 @@
+package amazonpay
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+// failingSigner holds a valid public key but fails to sign, like an unreachable signing daemon.
+type failingSigner struct {
+	crypto.Signer
+}
+
+func (failingSigner) Sign(io.Reader, []byte, crypto.SignerOpts) ([]byte, error) {
+	return nil, errors.New("signer unavailable")
+}
+
+func TestCircuitBreakerZeroValueDefaults(t *testing.T) {
+	b := &CircuitBreaker{}
+	for i := 1; i < DefaultFailureThreshold; i++ {
+		b.record("jp", "GetCharge", false, true)
+		if got := b.State("jp", "GetCharge"); got != CircuitClosed {
+			t.Fatalf("after %d failures: state %s, want closed", i, got)
+		}
+	}
+	b.record("jp", "GetCharge", false, true)
+	if got := b.State("jp", "GetCharge"); got != CircuitOpen {
+		t.Fatalf("after %d failures: state %s, want open", DefaultFailureThreshold, got)
+	}
+	var open *CircuitOpenError
+	if _, err := b.allow("jp", "GetCharge"); !errors.As(err, &open) || open.RetryAfter <= 0 {
+		t.Fatalf("allow = %v, want a CircuitOpenError with a positive RetryAfter", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresSignErrors(t *testing.T) {
+	sent := 0
+	rt := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		sent++
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	})
+	c := newTestClient(t, failingSigner{signingtest.Signer()}, rt)
+	c.CircuitBreaker = NewCircuitBreaker(1, 0)
+	ctx := withOperation(context.Background(), "GetCharge")
+
+	for i := 0; i < 3; i++ {
+		req, err := c.newRequest(http.MethodGet, "v2/charges/S03-1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.Do(ctx, req, nil); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Do = %v, want the signing error", err)
+		}
+	}
+	if sent != 0 {
+		t.Errorf("%d requests sent without signature", sent)
+	}
+	if got := c.CircuitBreaker.State("jp", "GetCharge"); got != CircuitClosed {
+		t.Errorf("state %s after signing errors, want closed", got)
+	}
+}
+
+func TestCircuitBreakerOpensOnTransportErrors(t *testing.T) {
+	rt := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+	c := newTestClient(t, signingtest.Signer(), rt)
+	c.CircuitBreaker = NewCircuitBreaker(1, 0)
+	ctx := withOperation(context.Background(), "GetCharge")
+
+	req, err := c.newRequest(http.MethodGet, "v2/charges/S03-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Do(ctx, req, nil); err == nil {
+		t.Fatal("Do succeeded, want the transport error")
+	}
+	if got := c.CircuitBreaker.State("jp", "GetCharge"); got != CircuitOpen {
+		t.Errorf("state %s after a transport error, want open", got)
+	}
+}
+
+// openCircuit returns a breaker with the jp/GetCharge circuit open since the open timeout elapsed,
+// so that the next request is the probe.
+func openCircuit(t *testing.T) *CircuitBreaker {
+	t.Helper()
+	b := NewCircuitBreaker(1, time.Minute)
+	if _, err := b.allow("jp", "GetCharge"); err != nil {
+		t.Fatal(err)
+	}
+	b.record("jp", "GetCharge", false, true)
+	b.circuits[circuitKey{region: "jp", operation: "GetCharge"}].openedAt = time.Now().Add(-time.Hour)
+	return b
+}
+
+func TestCircuitBreakerIgnoresLateOutcomes(t *testing.T) {
+	b := NewCircuitBreaker(2, time.Minute)
+	// A slow request is sent while the circuit is closed.
+	slow, err := b.allow("jp", "GetCharge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		probe, err := b.allow("jp", "GetCharge")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.record("jp", "GetCharge", probe, true)
+	}
+	if got := b.State("jp", "GetCharge"); got != CircuitOpen {
+		t.Fatalf("state %s, want open", got)
+	}
+	openedAt := b.circuits[circuitKey{region: "jp", operation: "GetCharge"}].openedAt
+
+	b.record("jp", "GetCharge", slow, false)
+	if got := b.State("jp", "GetCharge"); got != CircuitOpen {
+		t.Errorf("state %s after a late success, want open", got)
+	}
+	b.record("jp", "GetCharge", slow, true)
+	if got := b.circuits[circuitKey{region: "jp", operation: "GetCharge"}].openedAt; !got.Equal(openedAt) {
+		t.Error("a late failure extended the open timeout")
+	}
+}
+
+func TestCircuitBreakerProbe(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		failed bool
+		want   CircuitState
+	}{
+		{"success", false, CircuitClosed},
+		{"failure", true, CircuitOpen},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b := openCircuit(t)
+			probe, err := b.allow("jp", "GetCharge")
+			if err != nil || !probe {
+				t.Fatalf("allow = %t, %v, want the probe", probe, err)
+			}
+			if got := b.State("jp", "GetCharge"); got != CircuitHalfOpen {
+				t.Fatalf("state %s, want half-open", got)
+			}
+			if _, err := b.allow("jp", "GetCharge"); !errors.Is(err, ErrCircuitOpen) {
+				t.Fatalf("second request while probing: %v, want ErrCircuitOpen", err)
+			}
+			// A request sent before the circuit opened does not decide for the probe.
+			b.record("jp", "GetCharge", false, !tt.failed)
+			if got := b.State("jp", "GetCharge"); got != CircuitHalfOpen {
+				t.Fatalf("state %s after a late outcome, want half-open", got)
+			}
+
+			b.record("jp", "GetCharge", probe, tt.failed)
+			if got := b.State("jp", "GetCharge"); got != tt.want {
+				t.Errorf("state %s after the probe, want %s", got, tt.want)
+			}
+			_, err = b.allow("jp", "GetCharge")
+			if tt.failed != errors.Is(err, ErrCircuitOpen) {
+				t.Errorf("allow after the probe: %v", err)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerReleasedProbe(t *testing.T) {
+	b := openCircuit(t)
+	probe, err := b.allow("jp", "GetCharge")
+	if err != nil || !probe {
+		t.Fatalf("allow = %t, %v, want the probe", probe, err)
+	}
+	b.release("jp", "GetCharge", probe)
+	if probe, err := b.allow("jp", "GetCharge"); err != nil || !probe {
+		t.Errorf("allow after a released probe = %t, %v, want a new probe", probe, err)
+	}
+}
+
+func TestCircuitBreakerProbeThroughDo(t *testing.T) {
+	status := http.StatusInternalServerError
+	rt := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	})
+	c := newTestClient(t, signingtest.Signer(), rt)
+	c.CircuitBreaker = openCircuit(t)
+
+	status = http.StatusOK
+	if _, _, err := c.GetCharge(context.Background(), "S03-1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.CircuitBreaker.State("jp", "GetCharge"); got != CircuitClosed {
+		t.Errorf("state %s after a successful probe, want closed", got)
+	}
+}
@@ -3,6 +3,7 @@ package amazonpay
 import (
 	"bytes"
 	"context"
+	"crypto"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,10 +11,9 @@ import (
 	"net/http"
 	"net/url"
 	"runtime"
+	"sync/atomic"
 	"time"
 
-	"github.com/rs/xid"
-
 	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
 )
 
@@ -41,35 +41,72 @@ var (
 	}
 )
 
+var userAgent = fmt.Sprintf("amazon-pay-api-sdk-go/%s (GO/%s)", SDKVersion, runtime.Version())
+
 // Client type.
 type Client struct {
-	PublicKeyID string
-	PrivateKey  []byte
-	Region      string
-	Sandbox     bool
-	HTTPClient  *http.Client
+	// Keys holds the signing keys, requests are signed with the active one.
+	Keys       *Keyring
+	Region     string
+	Sandbox    bool
+	HTTPClient *http.Client
+	// Algorithm is the signature algorithm, signing.AlgorithmV1 when nil.
+	Algorithm signing.SignatureAlgorithm
+	// SignedHeaders selects the headers signed in addition to signing.RequiredHeaders, every header when nil.
+	SignedHeaders signing.HeaderPolicy
+	// Metrics receives request and charge outcome measurements when set.
+	Metrics Metrics
+	// CircuitBreaker fails requests fast while a region and operation is failing when set.
+	CircuitBreaker *CircuitBreaker
+	// CompensateClockSkew offsets x-amz-pay-date by the skew measured from response Date headers.
+	CompensateClockSkew bool
+	// ClockSkewThreshold is the skew above which OnClockSkew is called.
+	ClockSkewThreshold time.Duration
+	// OnClockSkew is called with the measured skew when it exceeds ClockSkewThreshold.
+	OnClockSkew func(skew time.Duration)
+	// Debug records what was signed and attaches it to InvalidRequestSignature error responses.
+	Debug bool
+	// ValidateRequests runs Validate on request bodies before sending them.
+	// A request failing validation is not sent and its ValidationErrors are returned.
+	ValidateRequests bool
 
-	endpoint *url.URL
+	endpoint  *url.URL
+	clockSkew atomic.Int64
 }
 
 // New returns a new pay client instance.
+// privateKey is an unencrypted RSA private key in any format accepted by keys.Parse, parsed once here.
 func New(publicKeyID string, privateKey []byte, region string, sandbox bool, httpClient *http.Client) (*Client, error) {
-	if publicKeyID == "" {
-		return nil, errors.New("missing publicKeyID")
-	}
 	if privateKey == nil {
 		return nil, errors.New("missing  privateKey")
 	}
+	signer, err := signing.ParsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithSigner(publicKeyID, signer, region, sandbox, httpClient)
+}
+
+// NewWithSigner returns a new pay client instance signing requests through signer,
+// such as a KMS or HSM backed crypto.Signer.
+func NewWithSigner(publicKeyID string, signer crypto.Signer, region string, sandbox bool, httpClient *http.Client) (*Client, error) {
 	if region == "" {
 		return nil, errors.New("missing region")
 	}
 	c := &Client{
-		PublicKeyID: publicKeyID,
-		PrivateKey:  privateKey,
-		Region:      region,
-		Sandbox:     sandbox,
-		HTTPClient:  httpClient,
+		Region:     region,
+		Sandbox:    sandbox,
+		HTTPClient: httpClient,
+	}
+	if err := c.checkKeyEnvironment(publicKeyID); err != nil {
+		return nil, err
+	}
+	keys, err := NewKeyring(publicKeyID, signer)
+	if err != nil {
+		return nil, err
 	}
+	keys.validate = c.checkKeyEnvironment
+	c.Keys = keys
 	endpointURL := c.createEndpointURL()
 	u, err := url.Parse(endpointURL)
 	if err != nil {
@@ -79,17 +116,34 @@ func New(publicKeyID string, privateKey []byte, region string, sandbox bool, htt
 	return c, nil
 }
 
-func (c *Client) createEndpointURL() string {
-	modePath := "live"
-	if c.Sandbox {
-		modePath = "sandbox"
+func (c *Client) algorithm() signing.SignatureAlgorithm {
+	if c.Algorithm == nil {
+		return signing.AlgorithmV1
 	}
+	return c.Algorithm
+}
+
+// createEndpointURL returns the API root. The live/sandbox segment is added per request by Do.
+func (c *Client) createEndpointURL() string {
 	host := RegionHostMap[RegionMap[c.Region]]
-	return "https://" + host + "/" + modePath + "/"
+	return "https://" + host + "/"
 }
 
 // NewRequest method.
+// path is relative to the API root, such as "v2/charges".
+// The request is signed with the active key and has the live/sandbox path segment the key requires,
+// so that it can be sent with any http.Client. Do signs it again, with the key selected on its context.
 func (c *Client) NewRequest(method, path string, body interface{}) (*http.Request, error) {
+	req, err := c.newRequest(method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.SignRequest(req.Context(), req)
+}
+
+// newRequest returns the unsigned request of NewRequest, without the live/sandbox path segment.
+// The endpoint methods send it with Do, which signs it once.
+func (c *Client) newRequest(method, path string, body interface{}) (*http.Request, error) {
 	u, err := c.endpoint.Parse(path)
 	if err != nil {
 		return nil, err
@@ -109,31 +163,9 @@ func (c *Client) NewRequest(method, path string, body interface{}) (*http.Reques
 		return nil, err
 	}
 
-	if method == http.MethodPost {
-		req.Header.Set("x-amz-pay-idempotency-key", xid.New().String())
-	}
-	req.Header.Set("x-amz-pay-region", c.Region)
-	req.Header.Set("x-amz-pay-host", RegionHostMap[RegionMap[c.Region]])
-	req.Header.Set("x-amz-pay-date", time.Now().UTC().Format("20060102T150405Z"))
 	req.Header.Set("content-type", "application/json")
 	req.Header.Set("accept", "application/json")
-	req.Header.Set("user-agent", fmt.Sprintf("amazon-pay-api-sdk-go/%s (GO/%s)", SDKVersion, runtime.Version()))
-
-	canonicalRequest, err := signing.CanonicalRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	stringToSign, err := signing.StringToSign(canonicalRequest)
-	if err != nil {
-		return nil, err
-	}
-	signature, err := signing.Sign(c.PrivateKey, stringToSign)
-	if err != nil {
-		return nil, err
-	}
-	signedHeaders := signing.SignedHeaders(req)
-	authValue := signing.AuthHeaderValue(c.PublicKeyID, signedHeaders, signature)
-	req.Header.Set("Authorization", authValue)
+	req.Header.Set("user-agent", userAgent)
 
 	return req, nil
 }
@@ -146,9 +178,64 @@ func (c *Client) handleResponseBody(resp *http.Response, v interface{}) error {
 	return json.NewDecoder(resp.Body).Decode(v)
 }
 
+// SignRequest returns a clone of req signed with the active key or the key selected by WithSigningKey on ctx,
+// and with the live/sandbox path segment the key requires, for sending it with another http.Client.
+// A request signed before, such as one returned by NewRequest, is signed again.
+func (c *Client) SignRequest(ctx context.Context, req *http.Request) (*http.Request, error) {
+	key, err := c.signingKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.Transport(nil, key).Sign(c.withEnvironmentPath(req, key).WithContext(ctx))
+}
+
+// Transport returns a transport signing requests with key and sending them with base.
+// It does not add the live/sandbox path segment, see SignRequest.
+func (c *Client) Transport(base http.RoundTripper, key Key) *signing.Transport {
+	return &signing.Transport{
+		Base:          base,
+		PublicKeyID:   key.PublicKeyID,
+		Signer:        key.Signer,
+		Algorithm:     c.algorithm(),
+		Region:        c.Region,
+		Host:          RegionHostMap[RegionMap[c.Region]],
+		Now:           c.now,
+		SignedHeaders: c.SignedHeaders,
+	}
+}
+
+// signingHTTPClient returns a copy of HTTPClient signing requests through Transport.
+func (c *Client) signingHTTPClient(key Key, onSigned func(req *http.Request, d *signing.Diagnostics)) *http.Client {
+	var httpClient http.Client
+	if c.HTTPClient != nil {
+		httpClient = *c.HTTPClient
+	}
+	transport := c.Transport(httpClient.Transport, key)
+	transport.OnSigned = onSigned
+	httpClient.Transport = transport
+	return &httpClient
+}
+
 func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
-	resp, err := c.HTTPClient.Do(req.WithContext(ctx))
+	key, err := c.signingKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	probe, err := c.allowRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var diagnostics *signing.Diagnostics
+	var onSigned func(req *http.Request, d *signing.Diagnostics)
+	if c.Debug {
+		onSigned = func(_ *http.Request, d *signing.Diagnostics) { diagnostics = d }
+	}
+	start := time.Now()
+	req = c.withEnvironmentPath(req, key)
+	resp, err := c.signingHTTPClient(key, onSigned).Do(req.WithContext(ctx))
+	c.recordResponse(ctx, probe, resp, err)
 	if err != nil {
+		c.observeRequest(ctx, 0, start)
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
@@ -157,11 +244,14 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*htt
 		return nil, err
 	}
 	defer resp.Body.Close()
+	c.observeRequest(ctx, resp.StatusCode, start)
+	c.trackClockSkew(resp, start)
 
 	if v != nil {
 		if err := c.handleResponseBody(resp, v); err != nil {
 			return resp, err
 		}
+		attachDiagnostics(diagnostics, v)
 	}
 	return resp, nil
 }
@@ -0,0 +1,35 @@
+package amazonpay
+
+import (
+	"net/http"
+	"time"
+)
+
+// ClockSkew returns the last measured difference between the server clock and the local clock.
+// A positive value means the local clock is behind.
+func (c *Client) ClockSkew() time.Duration {
+	return time.Duration(c.clockSkew.Load())
+}
+
+func (c *Client) now() time.Time {
+	if c.CompensateClockSkew {
+		return time.Now().Add(c.ClockSkew())
+	}
+	return time.Now()
+}
+
+// trackClockSkew measures the skew from the Date header of resp.
+// The local time is taken as the midpoint of the round trip, since the header only has second precision.
+func (c *Client) trackClockSkew(resp *http.Response, start time.Time) {
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+	end := time.Now()
+	localTime := start.Add(end.Sub(start) / 2)
+	skew := serverTime.Sub(localTime).Truncate(time.Second)
+	c.clockSkew.Store(int64(skew))
+	if c.OnClockSkew != nil && c.ClockSkewThreshold > 0 && (skew > c.ClockSkewThreshold || -skew > c.ClockSkewThreshold) {
+		c.OnClockSkew(skew)
+	}
+}
@@ -0,0 +1,109 @@
+package amazonpay
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+// skewedClient returns a client whose server clock is ahead by serverSkew, and the last request it sent.
+func skewedClient(t *testing.T, serverSkew time.Duration) (*Client, func() *http.Request) {
+	t.Helper()
+	var sent *http.Request
+	c := newTestClient(t, signingtest.Signer(), roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sent = req
+		resp, err := okResponse(req)
+		resp.Header = http.Header{"Date": {time.Now().Add(serverSkew).UTC().Format(http.TimeFormat)}}
+		return resp, err
+	}))
+	return c, func() *http.Request { return sent }
+}
+
+func sentDate(t *testing.T, req *http.Request) time.Time {
+	t.Helper()
+	date, err := time.Parse(signing.DateFormat, req.Header.Get("x-amz-pay-date"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return date
+}
+
+func within(got, want, margin time.Duration) bool {
+	return got >= want-margin && got <= want+margin
+}
+
+func TestClockSkew(t *testing.T) {
+	for _, serverSkew := range []time.Duration{10 * time.Minute, -10 * time.Minute} {
+		for _, compensate := range []bool{false, true} {
+			c, sent := skewedClient(t, serverSkew)
+			c.CompensateClockSkew = compensate
+			c.ClockSkewThreshold = 5 * time.Minute
+			var reported []time.Duration
+			c.OnClockSkew = func(skew time.Duration) { reported = append(reported, skew) }
+
+			for i := 0; i < 2; i++ {
+				if _, _, err := c.GetCharge(context.Background(), "S03-1"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !within(c.ClockSkew(), serverSkew, 2*time.Second) {
+				t.Errorf("skew %s: ClockSkew = %s", serverSkew, c.ClockSkew())
+			}
+			if len(reported) != 2 || !within(reported[0], serverSkew, 2*time.Second) {
+				t.Errorf("skew %s: OnClockSkew called with %v", serverSkew, reported)
+			}
+
+			// The first request was sent before any skew was measured, the second one after.
+			want := time.Duration(0)
+			if compensate {
+				want = serverSkew
+			}
+			if got := time.Until(sentDate(t, sent())); !within(got, want, 3*time.Second) {
+				t.Errorf("skew %s, compensate %t: x-amz-pay-date is %s from now, want %s", serverSkew, compensate, got, want)
+			}
+		}
+	}
+}
+
+func TestClockSkewThreshold(t *testing.T) {
+	c, _ := skewedClient(t, 2*time.Minute)
+	c.ClockSkewThreshold = 5 * time.Minute
+	called := false
+	c.OnClockSkew = func(time.Duration) { called = true }
+	if _, _, err := c.GetCharge(context.Background(), "S03-1"); err != nil {
+		t.Fatal(err)
+	}
+	if called {
+		t.Error("OnClockSkew called below ClockSkewThreshold")
+	}
+	if !within(c.ClockSkew(), 2*time.Minute, 2*time.Second) {
+		t.Errorf("ClockSkew = %s", c.ClockSkew())
+	}
+
+	// Without a threshold the callback is never called.
+	c.ClockSkewThreshold = 0
+	if _, _, err := c.GetCharge(context.Background(), "S03-1"); err != nil {
+		t.Fatal(err)
+	}
+	if called {
+		t.Error("OnClockSkew called without ClockSkewThreshold")
+	}
+}
+
+func TestClockSkewWithoutDate(t *testing.T) {
+	c, _ := skewedClient(t, time.Hour)
+	if _, _, err := c.GetCharge(context.Background(), "S03-1"); err != nil {
+		t.Fatal(err)
+	}
+	c.HTTPClient.Transport = roundTripFunc(okResponse)
+	if _, _, err := c.GetCharge(context.Background(), "S03-1"); err != nil {
+		t.Fatal(err)
+	}
+	if !within(c.ClockSkew(), time.Hour, 2*time.Second) {
+		t.Errorf("a response without Date changed ClockSkew to %s", c.ClockSkew())
+	}
+}
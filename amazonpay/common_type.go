@@ -46,8 +46,8 @@ type StatusDetails struct {
 	ReasonCode        string `json:"reasonCode,omitempty"`
 	ReasonDescription string `json:"reasonDescription,omitempty"`
 	// https://amazonpaycheckoutintegrationguide.s3.amazonaws.com/amazon-pay-api-v2/charge-permission.html#type-statusdetails
-	Reasons              []Reason `json:"reasons,omitempty"`
-	LastUpdatedTimestamp string   `json:"lastUpdatedTimestamp,omitempty"`
+	Reasons              []Reason  `json:"reasons,omitempty"`
+	LastUpdatedTimestamp Timestamp `json:"lastUpdatedTimestamp,omitempty"`
 }
 
 type Reason struct {
@@ -0,0 +1,26 @@
+package amazonpay
+
+import "github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+
+// ReasonCodeInvalidRequestSignature is returned by Amazon Pay when the request signature does not match.
+const ReasonCodeInvalidRequestSignature = "InvalidRequestSignature"
+
+type signingDiagnosticsSetter interface {
+	setSigningDiagnostics(d *signing.Diagnostics)
+}
+
+func (e *ErrorResponse) setSigningDiagnostics(d *signing.Diagnostics) {
+	if e.ReasonCode == ReasonCodeInvalidRequestSignature {
+		e.SigningDiagnostics = d
+	}
+}
+
+// attachDiagnostics sets the diagnostics recorded while signing to the error response in v.
+func attachDiagnostics(d *signing.Diagnostics, v interface{}) {
+	if d == nil {
+		return
+	}
+	if s, ok := v.(signingDiagnosticsSetter); ok {
+		s.setSigningDiagnostics(d)
+	}
+}
@@ -0,0 +1,160 @@
+package amazonpay
+
+// CheckoutSessionState is the StatusDetails.State of a checkout session.
+type CheckoutSessionState string
+
+const (
+	CheckoutSessionStateOpen      CheckoutSessionState = "Open"
+	CheckoutSessionStateCompleted CheckoutSessionState = "Completed"
+	CheckoutSessionStateCanceled  CheckoutSessionState = "Canceled"
+)
+
+// IsTerminal reports whether the checkout session can no longer change state.
+func (s CheckoutSessionState) IsTerminal() bool {
+	return s == CheckoutSessionStateCompleted || s == CheckoutSessionStateCanceled
+}
+
+// IsSuccessful reports whether the checkout session was completed.
+func (s CheckoutSessionState) IsSuccessful() bool {
+	return s == CheckoutSessionStateCompleted
+}
+
+// ChargeState is the StatusDetails.State of a charge.
+type ChargeState string
+
+const (
+	ChargeStateAuthorizationInitiated ChargeState = "AuthorizationInitiated"
+	ChargeStateAuthorized             ChargeState = "Authorized"
+	ChargeStateCaptureInitiated       ChargeState = "CaptureInitiated"
+	ChargeStateCaptured               ChargeState = "Captured"
+	ChargeStateCanceled               ChargeState = "Canceled"
+	ChargeStateDeclined               ChargeState = "Declined"
+)
+
+// IsTerminal reports whether the charge can no longer change state.
+func (s ChargeState) IsTerminal() bool {
+	switch s {
+	case ChargeStateCaptured, ChargeStateCanceled, ChargeStateDeclined:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSuccessful reports whether the charge was authorized or captured.
+// An authorized charge still has to be captured before it expires.
+func (s ChargeState) IsSuccessful() bool {
+	return s == ChargeStateAuthorized || s == ChargeStateCaptured
+}
+
+// RefundState is the StatusDetails.State of a refund.
+type RefundState string
+
+const (
+	RefundStateRefundInitiated RefundState = "RefundInitiated"
+	RefundStateRefunded        RefundState = "Refunded"
+	RefundStateDeclined        RefundState = "Declined"
+)
+
+// IsTerminal reports whether the refund can no longer change state.
+func (s RefundState) IsTerminal() bool {
+	return s == RefundStateRefunded || s == RefundStateDeclined
+}
+
+// IsSuccessful reports whether the refund was completed.
+func (s RefundState) IsSuccessful() bool {
+	return s == RefundStateRefunded
+}
+
+// ChargePermissionState is the StatusDetails.State of a charge permission.
+type ChargePermissionState string
+
+const (
+	ChargePermissionStateChargeable    ChargePermissionState = "Chargeable"
+	ChargePermissionStateNonChargeable ChargePermissionState = "NonChargeable"
+	ChargePermissionStateClosed        ChargePermissionState = "Closed"
+)
+
+// IsTerminal reports whether the charge permission can no longer change state.
+// A NonChargeable charge permission may become Chargeable again.
+func (s ChargePermissionState) IsTerminal() bool {
+	return s == ChargePermissionStateClosed
+}
+
+// IsSuccessful reports whether the charge permission can be charged.
+func (s ChargePermissionState) IsSuccessful() bool {
+	return s == ChargePermissionStateChargeable
+}
+
+// CheckoutSessionState returns State as a checkout session state, empty for nil status details.
+func (s *StatusDetails) CheckoutSessionState() CheckoutSessionState {
+	if s == nil {
+		return ""
+	}
+	return CheckoutSessionState(s.State)
+}
+
+// ChargeState returns State as a charge state.
+func (s *StatusDetails) ChargeState() ChargeState {
+	if s == nil {
+		return ""
+	}
+	return ChargeState(s.State)
+}
+
+// RefundState returns State as a refund state.
+func (s *StatusDetails) RefundState() RefundState {
+	if s == nil {
+		return ""
+	}
+	return RefundState(s.State)
+}
+
+// ChargePermissionState returns State as a charge permission state.
+func (s *StatusDetails) ChargePermissionState() ChargePermissionState {
+	if s == nil {
+		return ""
+	}
+	return ChargePermissionState(s.State)
+}
+
+// PaymentIntent is PaymentDetails.PaymentIntent.
+type PaymentIntent string
+
+const (
+	// PaymentIntentConfirm only confirms the checkout session, charges are created later.
+	PaymentIntentConfirm PaymentIntent = "Confirm"
+	// PaymentIntentAuthorize authorizes the charge amount when the checkout session is completed.
+	PaymentIntentAuthorize PaymentIntent = "Authorize"
+	// PaymentIntentAuthorizeWithCapture authorizes and captures the charge amount when the checkout session is completed.
+	PaymentIntentAuthorizeWithCapture PaymentIntent = "AuthorizeWithCapture"
+)
+
+// ChargePermissionType is the type of the charge permission created by a checkout session.
+type ChargePermissionType string
+
+const (
+	ChargePermissionTypeOneTime             ChargePermissionType = "OneTime"
+	ChargePermissionTypeRecurring           ChargePermissionType = "Recurring"
+	ChargePermissionTypePaymentMethodOnFile ChargePermissionType = "PaymentMethodOnFile"
+)
+
+// ProductType tells whether the buyer is asked for a shipping address, or only signs in.
+type ProductType string
+
+const (
+	ProductTypePayAndShip ProductType = "PayAndShip"
+	ProductTypePayOnly    ProductType = "PayOnly"
+	ProductTypeSignIn     ProductType = "SignIn"
+)
+
+// FrequencyUnit is the Frequency.Unit of a recurring charge permission.
+type FrequencyUnit string
+
+const (
+	FrequencyUnitYear     FrequencyUnit = "Year"
+	FrequencyUnitMonth    FrequencyUnit = "Month"
+	FrequencyUnitWeek     FrequencyUnit = "Week"
+	FrequencyUnitDay      FrequencyUnit = "Day"
+	FrequencyUnitVariable FrequencyUnit = "Variable"
+)
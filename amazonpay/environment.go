@@ -0,0 +1,74 @@
+package amazonpay
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	EnvironmentLive    = "live"
+	EnvironmentSandbox = "sandbox"
+)
+
+// KeyEnvironment returns the environment of a "LIVE-" or "SANDBOX-" prefixed public key id,
+// and an empty string for public key ids without an environment prefix.
+func KeyEnvironment(publicKeyID string) string {
+	switch {
+	case strings.HasPrefix(strings.ToUpper(publicKeyID), "LIVE-"):
+		return EnvironmentLive
+	case strings.HasPrefix(strings.ToUpper(publicKeyID), "SANDBOX-"):
+		return EnvironmentSandbox
+	default:
+		return ""
+	}
+}
+
+func (c *Client) environment() string {
+	if c.Sandbox {
+		return EnvironmentSandbox
+	}
+	return EnvironmentLive
+}
+
+// checkKeyEnvironment rejects a prefixed public key id of the other environment than Sandbox.
+func (c *Client) checkKeyEnvironment(publicKeyID string) error {
+	if env := KeyEnvironment(publicKeyID); env != "" && env != c.environment() {
+		return fmt.Errorf("public key id %s is a %s key but the client is configured for %s", publicKeyID, env, c.environment())
+	}
+	return nil
+}
+
+// withEnvironmentPath returns req with the live/sandbox path segment inserted for keys without an environment prefix.
+// Keys with an environment prefix are sent to the environment-less endpoint.
+// A segment already in the path, such as the one of a request returned by NewRequest or SignRequest, is replaced.
+func (c *Client) withEnvironmentPath(req *http.Request, key Key) *http.Request {
+	if req.URL.Host != c.endpoint.Host {
+		return req
+	}
+	prefix := ""
+	if KeyEnvironment(key.PublicKeyID) == "" {
+		prefix = "/" + c.environment()
+	}
+	u := *req.URL
+	u.Path = prefix + trimEnvironmentPath(u.Path)
+	if u.RawPath != "" {
+		u.RawPath = prefix + trimEnvironmentPath(u.RawPath)
+	}
+	if u.Path == req.URL.Path && u.RawPath == req.URL.RawPath {
+		return req
+	}
+	r := req.WithContext(req.Context())
+	r.URL = &u
+	return r
+}
+
+// trimEnvironmentPath removes a leading live/sandbox segment from path.
+func trimEnvironmentPath(path string) string {
+	for _, env := range []string{EnvironmentLive, EnvironmentSandbox} {
+		if rest, ok := strings.CutPrefix(path, "/"+env+"/"); ok {
+			return "/" + rest
+		}
+	}
+	return path
+}
@@ -0,0 +1,122 @@
+package amazonpay
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+func TestKeyEnvironment(t *testing.T) {
+	for id, want := range map[string]string{
+		"LIVE-AHXXXXXXXXXXXXXXXXXXXXXX":    EnvironmentLive,
+		"live-AHXXXXXXXXXXXXXXXXXXXXXX":    EnvironmentLive,
+		"SANDBOX-AHXXXXXXXXXXXXXXXXXXXXXX": EnvironmentSandbox,
+		"Sandbox-AHXXXXXXXXXXXXXXXXXXXXXX": EnvironmentSandbox,
+		"AHXXXXXXXXXXXXXXXXXXXXXX":         "",
+		"LIVEAHXXXXXXXXXXXXXXXXXXXX":       "",
+		"SANDBOX":                          "",
+		"":                                 "",
+	} {
+		if got := KeyEnvironment(id); got != want {
+			t.Errorf("KeyEnvironment(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestKeyEnvironmentMismatch(t *testing.T) {
+	for _, tt := range []struct {
+		publicKeyID string
+		sandbox     bool
+		wantErr     bool
+	}{
+		{"LIVE-AHXXXXXXXXXXXXXXXXXXXXXX", false, false},
+		{"LIVE-AHXXXXXXXXXXXXXXXXXXXXXX", true, true},
+		{"SANDBOX-AHXXXXXXXXXXXXXXXXXXXXXX", true, false},
+		{"SANDBOX-AHXXXXXXXXXXXXXXXXXXXXXX", false, true},
+		{"AHXXXXXXXXXXXXXXXXXXXXXX", false, false},
+		{"AHXXXXXXXXXXXXXXXXXXXXXX", true, false},
+	} {
+		_, err := NewWithSigner(tt.publicKeyID, signingtest.Signer(), "jp", tt.sandbox, nil)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewWithSigner(%s, sandbox %t) = %v, want error %t", tt.publicKeyID, tt.sandbox, err, tt.wantErr)
+		}
+
+		c, err := NewWithSigner("AHXXXXXXXXXXXXXXXXXXXXXX", signingtest.Signer(), "jp", tt.sandbox, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = c.Keys.Add(tt.publicKeyID, signingtest.Signer())
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Keyring.Add(%s) to a client with sandbox %t = %v, want error %t", tt.publicKeyID, tt.sandbox, err, tt.wantErr)
+		}
+	}
+
+	// A keyring without a client takes keys of either environment.
+	k, err := NewKeyring("LIVE-AHXXXXXXXXXXXXXXXXXXXXXX", signingtest.Signer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Add("SANDBOX-AHXXXXXXXXXXXXXXXXXXXXXX", signingtest.Signer()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEnvironmentPath(t *testing.T) {
+	const unprefixed = "AHXXXXXXXXXXXXXXXXXXXXXX"
+	for _, tt := range []struct {
+		sandbox  bool
+		prefixed string
+		wantPath string
+	}{
+		{true, "SANDBOX-AHXXXXXXXXXXXXXXXXXXXXXX", "/sandbox/v2/charges/S03-1"},
+		{false, "LIVE-AHXXXXXXXXXXXXXXXXXXXXXX", "/live/v2/charges/S03-1"},
+	} {
+		var sent *http.Request
+		rt := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			sent = req
+			return okResponse(req)
+		})
+		c, err := NewWithSigner(unprefixed, signingtest.Signer(), "jp", tt.sandbox, &http.Client{Transport: rt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Keys.Add(tt.prefixed, signingtest.Signer()); err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.Background()
+		prefixedCtx := WithSigningKey(ctx, tt.prefixed)
+
+		if _, _, err := c.GetCharge(ctx, "S03-1"); err != nil {
+			t.Fatal(err)
+		}
+		if sent.URL.Path != tt.wantPath {
+			t.Errorf("unprefixed key: sent to %s, want %s", sent.URL.Path, tt.wantPath)
+		}
+		if _, _, err := c.GetCharge(prefixedCtx, "S03-1"); err != nil {
+			t.Fatal(err)
+		}
+		if sent.URL.Path != "/v2/charges/S03-1" {
+			t.Errorf("prefixed key: sent to %s, want no environment segment", sent.URL.Path)
+		}
+
+		// NewRequest adds the segment of the active, unprefixed key, which Do drops for a prefixed one.
+		req, err := c.NewRequest(http.MethodGet, "v2/charges/S03-1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if req.URL.Path != tt.wantPath {
+			t.Errorf("NewRequest path %s, want %s", req.URL.Path, tt.wantPath)
+		}
+		if _, err := c.Do(prefixedCtx, req, nil); err != nil {
+			t.Fatal(err)
+		}
+		if sent.URL.Path != "/v2/charges/S03-1" {
+			t.Errorf("NewRequest sent with a prefixed key to %s, want no environment segment", sent.URL.Path)
+		}
+		if got := sentKeyID(t, sent); got != tt.prefixed {
+			t.Errorf("signed with %s, want %s", got, tt.prefixed)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package amazonpay
 
+import "github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+
 type ErrorResponse struct {
 	ReasonCode string `json:"reasonCode,omitempty"`
 	Message    string `json:"message,omitempty"`
+	// SigningDiagnostics is set on InvalidRequestSignature errors when Client.Debug is enabled.
+	SigningDiagnostics *signing.Diagnostics `json:"-"`
 }
@@ -0,0 +1,333 @@
+package amazonpay
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// modelFieldsCache maps a struct type to its modelFields.
+var modelFieldsCache sync.Map
+
+// modelField is a JSON field of a response model.
+type modelField struct {
+	name  string
+	index []int
+}
+
+// modelFields returns the JSON fields of struct type t, including those of embedded structs.
+func modelFields(t reflect.Type) []modelField {
+	if fields, ok := modelFieldsCache.Load(t); ok {
+		return fields.([]modelField) //nolint:forcetypeassert // only slices are stored
+	}
+	var fields []modelField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for _, embedded := range modelFields(f.Type) {
+				fields = append(fields, modelField{name: embedded.name, index: append([]int{i}, embedded.index...)})
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, modelField{name: name, index: []int{i}})
+	}
+	modelFieldsCache.Store(t, fields)
+	return fields
+}
+
+// lookupField returns the field key decodes into: the exact match,
+// else the first case-insensitive one, like encoding/json.
+func lookupField(fields []modelField, key string) (modelField, bool) {
+	for _, f := range fields {
+		if f.name == key {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return modelField{}, false
+}
+
+// unmarshalModel decodes data into plain, a pointer to a method-less copy of a response model,
+// and returns the fields of data not modeled by it.
+func unmarshalModel(data []byte, plain interface{}) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, plain); err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	modeled := modelFields(reflect.TypeOf(plain).Elem())
+	var extra map[string]json.RawMessage
+	for k, v := range fields {
+		if _, ok := lookupField(modeled, k); ok {
+			continue
+		}
+		if extra == nil {
+			extra = map[string]json.RawMessage{}
+		}
+		extra[k] = v
+	}
+	return extra, nil
+}
+
+// member is a field of a JSON object, in order of appearance.
+type member struct {
+	key   string
+	value json.RawMessage
+}
+
+// objectMembers returns the members of the JSON object data, duplicates included.
+func objectMembers(data []byte) ([]member, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	var members []member
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		members = append(members, member{key: key, value: value})
+	}
+	return members, nil
+}
+
+// marshalModel encodes plain, a method-less copy of a response model, with the extra fields.
+//
+// A model decoded from raw is encoded as raw, with the members holding a modeled field
+// that has been changed since replaced by its new value, and the members not modeled by the values of extra.
+// Nulls, empty values and nested fields of unchanged members are thus kept as received.
+// Without raw, or for extra fields not in raw, the extra fields follow the modeled ones.
+// Extra fields shadowed by a modeled field are skipped.
+func marshalModel(plain interface{}, extra map[string]json.RawMessage, raw json.RawMessage) ([]byte, error) {
+	b, err := json.Marshal(plain)
+	if err != nil {
+		return nil, err
+	}
+	fields := modelFields(reflect.TypeOf(plain))
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	write := func(key string, value []byte) error {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+		return nil
+	}
+
+	written := map[string]bool{}
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '{' {
+		members, err := objectMembers(raw)
+		if err != nil {
+			return nil, err
+		}
+		received := reflect.New(reflect.TypeOf(plain))
+		if err := json.Unmarshal(raw, received.Interface()); err != nil {
+			return nil, err
+		}
+		current := reflect.ValueOf(plain)
+		changed := map[string]bool{}
+		for _, f := range fields {
+			was, err := json.Marshal(received.Elem().FieldByIndex(f.index).Interface())
+			if err != nil {
+				return nil, err
+			}
+			is, err := json.Marshal(current.FieldByIndex(f.index).Interface())
+			if err != nil {
+				return nil, err
+			}
+			changed[f.name] = !bytes.Equal(was, is)
+		}
+		last := map[string]json.RawMessage{}
+		for _, m := range members {
+			last[m.key] = m.value
+		}
+
+		modeled, err := objectMembers(b)
+		if err != nil {
+			return nil, err
+		}
+		values := map[string]json.RawMessage{}
+		for _, m := range modeled {
+			values[m.key] = m.value
+		}
+		for _, m := range members {
+			if f, ok := lookupField(fields, m.key); ok {
+				switch {
+				case !changed[f.name]:
+					err = write(m.key, m.value)
+				case !written[f.name] && values[f.name] != nil:
+					err = write(f.name, values[f.name])
+				}
+				written[f.name] = true
+			} else if value, ok := extra[m.key]; ok {
+				switch {
+				case bytes.Equal(value, last[m.key]):
+					err = write(m.key, m.value)
+				case !written[m.key]:
+					err = write(m.key, value)
+				}
+				written[m.key] = true
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, m := range modeled {
+			if !written[m.key] && changed[m.key] {
+				if err := write(m.key, m.value); err != nil {
+					return nil, err
+				}
+				written[m.key] = true
+			}
+		}
+	} else {
+		modeled, err := objectMembers(b)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range modeled {
+			if err := write(m.key, m.value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		if _, ok := lookupField(fields, k); !ok && !written[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := write(k, extra[k]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes the charge, keeping unmodeled fields in Extra and the whole object for Raw.
+func (c *Charge) UnmarshalJSON(data []byte) error {
+	type plain Charge
+	extra, err := unmarshalModel(data, (*plain)(c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	c.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON encodes the charge as decoded, with the changed fields and those in Extra.
+func (c Charge) MarshalJSON() ([]byte, error) {
+	type plain Charge
+	return marshalModel(plain(c), c.Extra, c.raw)
+}
+
+// Raw returns the JSON object the charge was decoded from, nil if it was not decoded.
+func (c *Charge) Raw() json.RawMessage {
+	return c.raw
+}
+
+// UnmarshalJSON decodes the refund, keeping unmodeled fields in Extra and the whole object for Raw.
+func (r *Refund) UnmarshalJSON(data []byte) error {
+	type plain Refund
+	extra, err := unmarshalModel(data, (*plain)(r))
+	if err != nil {
+		return err
+	}
+	r.Extra = extra
+	r.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON encodes the refund as decoded, with the changed fields and those in Extra.
+func (r Refund) MarshalJSON() ([]byte, error) {
+	type plain Refund
+	return marshalModel(plain(r), r.Extra, r.raw)
+}
+
+// Raw returns the JSON object the refund was decoded from, nil if it was not decoded.
+func (r *Refund) Raw() json.RawMessage {
+	return r.raw
+}
+
+// UnmarshalJSON decodes the checkout session, keeping unmodeled fields in Extra and the whole object for Raw.
+func (s *CheckoutSession) UnmarshalJSON(data []byte) error {
+	type plain CheckoutSession
+	extra, err := unmarshalModel(data, (*plain)(s))
+	if err != nil {
+		return err
+	}
+	s.Extra = extra
+	s.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON encodes the checkout session as decoded, with the changed fields and those in Extra.
+func (s CheckoutSession) MarshalJSON() ([]byte, error) {
+	type plain CheckoutSession
+	return marshalModel(plain(s), s.Extra, s.raw)
+}
+
+// Raw returns the JSON object the checkout session was decoded from, nil if it was not decoded.
+func (s *CheckoutSession) Raw() json.RawMessage {
+	return s.raw
+}
+
+// UnmarshalJSON decodes the charge permission, keeping unmodeled fields in Extra and the whole object for Raw.
+func (p *ChargePermission) UnmarshalJSON(data []byte) error {
+	type plain ChargePermission
+	extra, err := unmarshalModel(data, (*plain)(p))
+	if err != nil {
+		return err
+	}
+	p.Extra = extra
+	p.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON encodes the charge permission as decoded, with the changed fields and those in Extra.
+func (p ChargePermission) MarshalJSON() ([]byte, error) {
+	type plain ChargePermission
+	return marshalModel(plain(p), p.Extra, p.raw)
+}
+
+// Raw returns the JSON object the charge permission was decoded from, nil if it was not decoded.
+func (p *ChargePermission) Raw() json.RawMessage {
+	return p.raw
+}
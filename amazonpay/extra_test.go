@@ -0,0 +1,116 @@
+package amazonpay
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func compact(t *testing.T, s string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestModelRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		v    interface{}
+		data string
+	}{
+		{"charge", &Charge{}, `{"chargeId":"S01","convertedAmount":null,"softDescriptor":"","providerMetadata":{"providerReferenceId":null,"newNested":1},"ChargeID":"dup","newTop":{"a":1}}`},
+		{"charge with duplicate unknown", &Charge{}, `{"x":1,"chargeAmount":{"amount":"10","currencyCode":"JPY","fx":true},"x":2}`},
+		{"refund", &Refund{}, `{"refundId":"S01-R","refundAmount":{"amount":"1","currencyCode":"JPY"},"softDescriptor":null,"RELEASEENVIRONMENT":"Sandbox","newTop":[1,2]}`},
+		{"checkout session", &CheckoutSession{}, `{"checkoutSessionId":"cs","webCheckoutDetails":{"checkoutReviewReturnUrl":"","amazonPayRedirectUrl":null,"newNested":"x"},"buyer":null,"newTop":"y"}`},
+		{"charge permission", &ChargePermission{}, `{"chargePermissionId":"B01","limits":{"amountLimit":{"amount":"1","currencyCode":"JPY"},"newNested":{}},"platformId":"","newTop":false}`},
+		{"empty", &Charge{}, `{}`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.data), tt.v); err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.Marshal(tt.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := compact(t, tt.data); string(got) != want {
+				t.Errorf("re-marshalled to\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestModelExtraIsCaseInsensitive(t *testing.T) {
+	var c Charge
+	if err := json.Unmarshal([]byte(`{"chargeId":"S01","ChargeID":"dup","newTop":1}`), &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.ChargeID != "dup" {
+		t.Errorf("ChargeID %q, want the last of the case-insensitive matches like encoding/json", c.ChargeID)
+	}
+	if _, ok := c.Extra["ChargeID"]; ok || len(c.Extra) != 1 {
+		t.Errorf("Extra %v, want only newTop", c.Extra)
+	}
+}
+
+func TestModelMarshalChanges(t *testing.T) {
+	const data = `{"chargeId":"S01","softDescriptor":"","providerMetadata":{"providerReferenceId":"P","newNested":1},"ChargeID":"dup","newTop":{"a":1},"gone":true}`
+	for _, tt := range []struct {
+		name   string
+		change func(c *Charge)
+		want   string
+	}{
+		{"modeled field", func(c *Charge) { c.SoftDescriptor = "AMZ" },
+			`{"chargeId":"S01","softDescriptor":"AMZ","providerMetadata":{"providerReferenceId":"P","newNested":1},"ChargeID":"dup","newTop":{"a":1},"gone":true}`},
+		{"duplicated field", func(c *Charge) { c.ChargeID = "S02" },
+			`{"chargeId":"S02","softDescriptor":"","providerMetadata":{"providerReferenceId":"P","newNested":1},"newTop":{"a":1},"gone":true}`},
+		{"nested field", func(c *Charge) { c.ProviderMetadata = &ProviderMetadata{ProviderReferenceID: "Q"} },
+			`{"chargeId":"S01","softDescriptor":"","providerMetadata":{"providerReferenceId":"Q"},"ChargeID":"dup","newTop":{"a":1},"gone":true}`},
+		{"cleared field", func(c *Charge) { c.ProviderMetadata = nil },
+			`{"chargeId":"S01","softDescriptor":"","ChargeID":"dup","newTop":{"a":1},"gone":true}`},
+		{"new field", func(c *Charge) { c.ReleaseEnvironment = "Live" },
+			`{"chargeId":"S01","softDescriptor":"","providerMetadata":{"providerReferenceId":"P","newNested":1},"ChargeID":"dup","newTop":{"a":1},"gone":true,"releaseEnvironment":"Live"}`},
+		{"extra fields", func(c *Charge) {
+			c.Extra["newTop"] = json.RawMessage(`2`)
+			delete(c.Extra, "gone")
+			c.Extra["added"] = json.RawMessage(`"z"`)
+			c.Extra["chargeid"] = json.RawMessage(`"shadowed"`)
+		}, `{"chargeId":"S01","softDescriptor":"","providerMetadata":{"providerReferenceId":"P","newNested":1},"ChargeID":"dup","newTop":2,"added":"z"}`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Charge
+			if err := json.Unmarshal([]byte(data), &c); err != nil {
+				t.Fatal(err)
+			}
+			tt.change(&c)
+			got, err := json.Marshal(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("marshalled to\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModelMarshalWithoutRaw(t *testing.T) {
+	c := Charge{
+		ChargeID: "S01",
+		Extra: map[string]json.RawMessage{
+			"zeta":     json.RawMessage(`1`),
+			"alpha":    json.RawMessage(`{"a":null}`),
+			"CHARGEID": json.RawMessage(`"shadowed"`),
+		},
+	}
+	got, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"chargeId":"S01","alpha":{"a":null},"zeta":1}`; string(got) != want {
+		t.Errorf("marshalled to\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,28 @@
+package amazonpay
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// newTestClient returns a jp sandbox client of the signingtest key id, sending requests with rt.
+func newTestClient(t testing.TB, signer crypto.Signer, rt http.RoundTripper) *Client {
+	t.Helper()
+	c, err := NewWithSigner(signingtest.PublicKeyID, signer, "jp", true, &http.Client{Transport: rt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func lookupTestKey(string) (*rsa.PublicKey, error) {
+	return signingtest.Signer().Public().(*rsa.PublicKey), nil
+}
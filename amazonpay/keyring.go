@@ -0,0 +1,147 @@
+package amazonpay
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+)
+
+// Key is a public key id registered in Seller Central and the signer of its private key.
+type Key struct {
+	PublicKeyID string
+	Signer      crypto.Signer
+}
+
+// Keyring holds the keys of a merchant and the active one used to sign requests.
+// It is safe for concurrent use, a request is signed with the key that was active when it was sent.
+type Keyring struct {
+	mu     sync.RWMutex
+	keys   map[string]crypto.Signer
+	active Key
+	// validate is set by the Client to reject keys of the other environment.
+	validate func(publicKeyID string) error
+}
+
+// NewKeyring returns a keyring holding a single active key.
+func NewKeyring(publicKeyID string, signer crypto.Signer) (*Keyring, error) {
+	k := &Keyring{keys: map[string]crypto.Signer{}}
+	if err := k.Add(publicKeyID, signer); err != nil {
+		return nil, err
+	}
+	k.active = Key{PublicKeyID: publicKeyID, Signer: signer}
+	return k, nil
+}
+
+// Add registers a key without activating it.
+func (k *Keyring) Add(publicKeyID string, signer crypto.Signer) error {
+	if publicKeyID == "" {
+		return errors.New("missing publicKeyID")
+	}
+	if err := signing.ValidateSigner(signer); err != nil {
+		return err
+	}
+	if k.validate != nil {
+		if err := k.validate(publicKeyID); err != nil {
+			return err
+		}
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.keys == nil {
+		k.keys = map[string]crypto.Signer{}
+	}
+	k.keys[publicKeyID] = signer
+	return nil
+}
+
+// Remove unregisters a key. The active key cannot be removed.
+func (k *Keyring) Remove(publicKeyID string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.active.PublicKeyID == publicKeyID {
+		return fmt.Errorf("cannot remove active key %s", publicKeyID)
+	}
+	delete(k.keys, publicKeyID)
+	return nil
+}
+
+// Activate makes a registered key the one used for new requests.
+func (k *Keyring) Activate(publicKeyID string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	signer, ok := k.keys[publicKeyID]
+	if !ok {
+		return fmt.Errorf("unknown key %s", publicKeyID)
+	}
+	k.active = Key{PublicKeyID: publicKeyID, Signer: signer}
+	return nil
+}
+
+// Active returns the active key.
+func (k *Keyring) Active() Key {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.active
+}
+
+// Get returns a registered key.
+func (k *Keyring) Get(publicKeyID string) (Key, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	signer, ok := k.keys[publicKeyID]
+	return Key{PublicKeyID: publicKeyID, Signer: signer}, ok
+}
+
+// PublicKeyIDs returns the ids of the registered keys in sorted order.
+func (k *Keyring) PublicKeyIDs() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+type signingKeyKey struct{}
+
+// WithSigningKey returns a context making Client.Do sign with the registered key publicKeyID
+// instead of the active one.
+func WithSigningKey(ctx context.Context, publicKeyID string) context.Context {
+	return context.WithValue(ctx, signingKeyKey{}, publicKeyID)
+}
+
+// signingKey returns the key set by WithSigningKey, or the active key,
+// after checking that it belongs to the environment of the client.
+func (c *Client) signingKey(ctx context.Context) (Key, error) {
+	key := c.Keys.Active()
+	if publicKeyID, ok := ctx.Value(signingKeyKey{}).(string); ok {
+		if key, ok = c.Keys.Get(publicKeyID); !ok {
+			return Key{}, fmt.Errorf("unknown key %s", publicKeyID)
+		}
+	}
+	if err := c.checkKeyEnvironment(key.PublicKeyID); err != nil {
+		return Key{}, err
+	}
+	return key, nil
+}
+
+// CheckKey runs GetChargePermission for a known chargePermissionID signed with the registered key publicKeyID.
+// It returns nil when Amazon Pay accepts the key, so that the key can be activated.
+func (c *Client) CheckKey(ctx context.Context, publicKeyID, chargePermissionID string) error {
+	resp, httpResp, err := c.GetChargePermission(WithSigningKey(ctx, publicKeyID), chargePermissionID)
+	if err != nil {
+		return err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("key %s: %s: %s %s", publicKeyID, httpResp.Status, resp.ReasonCode, resp.Message)
+	}
+	return nil
+}
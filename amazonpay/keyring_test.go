@@ -0,0 +1,200 @@
+package amazonpay
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+const nextKeyID = "SANDBOX-NEXTKEY0000000000000000"
+
+// sentKeyID returns the public key id req was signed with, after verifying its signature.
+func sentKeyID(t *testing.T, req *http.Request) string {
+	t.Helper()
+	if err := signing.VerifyRequest(req, lookupTestKey); err != nil {
+		t.Error(err)
+	}
+	auth, err := signing.ParseAuthHeaderValue(req.Header.Get("Authorization"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth.PublicKeyID
+}
+
+func okResponse(*http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+}
+
+func TestKeyring(t *testing.T) {
+	k, err := NewKeyring(signingtest.PublicKeyID, signingtest.Signer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Activate(nextKeyID); err == nil {
+		t.Error("activated an unknown key")
+	}
+	if err := k.Add(nextKeyID, signingtest.Signer()); err != nil {
+		t.Fatal(err)
+	}
+	if got := k.Active().PublicKeyID; got != signingtest.PublicKeyID {
+		t.Errorf("Add activated the key: active %s", got)
+	}
+	if err := k.Activate(nextKeyID); err != nil {
+		t.Fatal(err)
+	}
+	if got := k.Active(); got.PublicKeyID != nextKeyID || got.Signer == nil {
+		t.Errorf("active %+v, want %s", got, nextKeyID)
+	}
+	if got := k.PublicKeyIDs(); len(got) != 2 || got[0] != nextKeyID || got[1] != signingtest.PublicKeyID {
+		t.Errorf("PublicKeyIDs = %v", got)
+	}
+
+	if err := k.Remove(nextKeyID); err == nil {
+		t.Error("removed the active key")
+	}
+	if err := k.Remove(signingtest.PublicKeyID); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := k.Get(signingtest.PublicKeyID); ok {
+		t.Error("Get found a removed key")
+	}
+	if err := k.Activate(signingtest.PublicKeyID); err == nil {
+		t.Error("activated a removed key")
+	}
+	if got := k.Active().PublicKeyID; got != nextKeyID {
+		t.Errorf("active %s after failed calls, want %s", got, nextKeyID)
+	}
+
+	if err := k.Add("", signingtest.Signer()); err == nil {
+		t.Error("added an empty key id")
+	}
+	if err := k.Add("OTHER", nil); err == nil {
+		t.Error("added a nil signer")
+	}
+}
+
+func TestWithSigningKey(t *testing.T) {
+	var sent *http.Request
+	c := newTestClient(t, signingtest.Signer(), roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sent = req
+		return okResponse(req)
+	}))
+	if err := c.Keys.Add(nextKeyID, signingtest.Signer()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := c.GetCharge(context.Background(), "S03-1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := sentKeyID(t, sent); got != signingtest.PublicKeyID {
+		t.Errorf("signed with %s, want the active key", got)
+	}
+	if _, _, err := c.GetCharge(WithSigningKey(context.Background(), nextKeyID), "S03-1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := sentKeyID(t, sent); got != nextKeyID {
+		t.Errorf("signed with %s, want the key of WithSigningKey", got)
+	}
+	if got := c.Keys.Active().PublicKeyID; got != signingtest.PublicKeyID {
+		t.Errorf("WithSigningKey activated %s", got)
+	}
+
+	sent = nil
+	if _, _, err := c.GetCharge(WithSigningKey(context.Background(), "SANDBOX-UNKNOWN"), "S03-1"); err == nil || !strings.Contains(err.Error(), "unknown key") {
+		t.Errorf("GetCharge with an unknown key: %v", err)
+	}
+	if sent != nil {
+		t.Error("a request was sent with an unknown key")
+	}
+}
+
+func TestCheckKey(t *testing.T) {
+	var sent *http.Request
+	status := http.StatusOK
+	c := newTestClient(t, signingtest.Signer(), roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sent = req
+		body := `{"chargePermissionId":"B03-1"}`
+		if status != http.StatusOK {
+			body = `{"reasonCode":"InvalidRequestSignature","message":"bad key"}`
+		}
+		return &http.Response{StatusCode: status, Status: http.StatusText(status), Body: io.NopCloser(strings.NewReader(body))}, nil
+	}))
+	if err := c.Keys.Add(nextKeyID, signingtest.Signer()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.CheckKey(context.Background(), nextKeyID, "B03-1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := sentKeyID(t, sent); got != nextKeyID {
+		t.Errorf("checked with %s, want %s", got, nextKeyID)
+	}
+	if !strings.HasSuffix(sent.URL.Path, "/v2/chargePermissions/B03-1") {
+		t.Errorf("checked with %s", sent.URL.Path)
+	}
+
+	status = http.StatusUnauthorized
+	if err := c.CheckKey(context.Background(), nextKeyID, "B03-1"); err == nil || !strings.Contains(err.Error(), "InvalidRequestSignature") {
+		t.Errorf("CheckKey of a rejected key: %v", err)
+	}
+	if err := c.CheckKey(context.Background(), "SANDBOX-UNKNOWN", "B03-1"); err == nil {
+		t.Error("CheckKey of an unknown key succeeded")
+	}
+}
+
+// TestActivateDuringDo is meant for go test -race.
+func TestActivateDuringDo(t *testing.T) {
+	var mu sync.Mutex
+	var sent []*http.Request
+	c := newTestClient(t, signingtest.Signer(), roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		sent = append(sent, req)
+		mu.Unlock()
+		return okResponse(req)
+	}))
+	if err := c.Keys.Add(nextKeyID, signingtest.Signer()); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if _, _, err := c.GetCharge(context.Background(), "S03-1"); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				id := signingtest.PublicKeyID
+				if j%2 == 0 {
+					id = nextKeyID
+				}
+				if err := c.Keys.Activate(id); err != nil {
+					t.Error(err)
+				}
+				c.Keys.PublicKeyIDs()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(sent) != 40 {
+		t.Fatalf("%d requests sent, want 40", len(sent))
+	}
+	for _, req := range sent {
+		if id := sentKeyID(t, req); id != signingtest.PublicKeyID && id != nextKeyID {
+			t.Errorf("signed with %s", id)
+		}
+	}
+}
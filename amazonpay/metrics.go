@@ -0,0 +1,47 @@
+package amazonpay
+
+import (
+	"context"
+	"time"
+)
+
+// Metrics records client side measurements.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveRequest is called once per API call. statusCode is 0 when no response was received.
+	ObserveRequest(operation, region string, statusCode int, duration time.Duration)
+	// ObserveChargeOutcome is called for every charge returned by CreateCharge and CaptureCharge.
+	// GetCharge is not observed so that polling a charge does not count its outcome again.
+	ObserveChargeOutcome(operation, region, state, reasonCode string)
+}
+
+type operationKey struct{}
+
+// OperationUnknown is the operation name reported for requests sent with Do directly.
+const OperationUnknown = "Unknown"
+
+func withOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation)
+}
+
+// OperationFromContext returns the API operation name set by the endpoint methods.
+func OperationFromContext(ctx context.Context) string {
+	if op, ok := ctx.Value(operationKey{}).(string); ok {
+		return op
+	}
+	return OperationUnknown
+}
+
+func (c *Client) observeRequest(ctx context.Context, statusCode int, start time.Time) {
+	if c.Metrics == nil {
+		return
+	}
+	c.Metrics.ObserveRequest(OperationFromContext(ctx), c.Region, statusCode, time.Since(start))
+}
+
+func (c *Client) observeChargeOutcome(ctx context.Context, statusDetails *StatusDetails) {
+	if c.Metrics == nil || statusDetails == nil {
+		return
+	}
+	c.Metrics.ObserveChargeOutcome(OperationFromContext(ctx), c.Region, statusDetails.State, statusDetails.ReasonCode)
+}
@@ -0,0 +1,68 @@
+package amazonpay
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+type recordedMetrics struct {
+	mu       sync.Mutex
+	requests []string
+	outcomes []string
+}
+
+func (m *recordedMetrics) ObserveRequest(operation, region string, statusCode int, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, strings.Join([]string{operation, region, http.StatusText(statusCode)}, " "))
+}
+
+func (m *recordedMetrics) ObserveChargeOutcome(operation, region, state, reasonCode string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.outcomes = append(m.outcomes, strings.Join([]string{operation, region, state, reasonCode}, " "))
+}
+
+func chargeResponse(state, reasonCode string) roundTripFunc {
+	return func(*http.Request) (*http.Response, error) {
+		body := `{"chargeId":"S03-1","statusDetails":{"state":"` + state + `","reasonCode":"` + reasonCode + `"}}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	m := &recordedMetrics{}
+	c := newTestClient(t, signingtest.Signer(), chargeResponse("Authorized", ""))
+	c.Metrics = m
+	ctx := context.Background()
+
+	if _, _, err := c.CreateCharge(ctx, &CreateChargeRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	// Polling the charge returns the same outcome again and must not count it.
+	for i := 0; i < 3; i++ {
+		if _, _, err := c.GetCharge(ctx, "S03-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.HTTPClient.Transport = chargeResponse("Declined", "HardDeclined")
+	if _, _, err := c.CaptureCharge(ctx, "S03-1", &CaptureChargeRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	wantRequests := []string{"CreateCharge jp OK", "GetCharge jp OK", "GetCharge jp OK", "GetCharge jp OK", "CaptureCharge jp OK"}
+	if strings.Join(m.requests, "\n") != strings.Join(wantRequests, "\n") {
+		t.Errorf("requests %q, want %q", m.requests, wantRequests)
+	}
+	wantOutcomes := []string{"CreateCharge jp Authorized ", "CaptureCharge jp Declined HardDeclined"}
+	if strings.Join(m.outcomes, "\n") != strings.Join(wantOutcomes, "\n") {
+		t.Errorf("charge outcomes %q, want %q", m.outcomes, wantOutcomes)
+	}
+}
@@ -0,0 +1,272 @@
+package amazonpay
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrCurrencyMismatch is returned when combining amounts of different currencies.
+	ErrCurrencyMismatch = errors.New("amazonpay: currency mismatch")
+	// ErrPrecision is returned for an amount with more decimal places than its currency allows.
+	ErrPrecision = errors.New("amazonpay: amount precision not allowed by currency")
+	// ErrUnknownCurrency is returned for a currency without known minor units.
+	ErrUnknownCurrency = errors.New("amazonpay: unknown currency")
+	// ErrAmountOverflow is returned when an amount does not fit in 64 bits of minor units.
+	ErrAmountOverflow = errors.New("amazonpay: amount overflow")
+)
+
+// CurrencyMinorUnits holds the number of decimal places of the currencies supported by Amazon Pay.
+var CurrencyMinorUnits = map[string]int{
+	"AUD": 2,
+	"CHF": 2,
+	"DKK": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"HKD": 2,
+	"JPY": 0,
+	"NOK": 2,
+	"NZD": 2,
+	"SEK": 2,
+	"USD": 2,
+	"ZAR": 2,
+}
+
+// RoundingMode selects how an amount is rounded to the minor units of its currency.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest minor unit, ties away from zero.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest minor unit, ties to even.
+	RoundHalfEven
+	// RoundDown rounds toward zero.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+)
+
+// Money is an exact amount in the minor units of a currency, such as cents for USD or yen for JPY.
+// The zero value has no currency.
+type Money struct {
+	units    int64
+	currency string
+}
+
+// NewMoney returns units minor units of currency.
+func NewMoney(units int64, currency string) (Money, error) {
+	if _, err := minorUnits(currency); err != nil {
+		return Money{}, err
+	}
+	return Money{units: units, currency: currency}, nil
+}
+
+// ParseMoney parses a decimal amount such as "10.50".
+// It returns ErrPrecision when amount has non-zero digits below the minor unit of currency.
+func ParseMoney(amount, currency string) (Money, error) {
+	r, err := parseDecimal(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	digits, err := minorUnits(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(digits)))
+	if !scaled.IsInt() {
+		return Money{}, fmt.Errorf("%w: %s %s", ErrPrecision, amount, currency)
+	}
+	return moneyFromInt(scaled.Num(), currency)
+}
+
+// RoundMoney parses a decimal amount of any precision and rounds it to the minor units of currency.
+func RoundMoney(amount, currency string, mode RoundingMode) (Money, error) {
+	r, err := parseDecimal(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return roundMoney(r, currency, mode)
+}
+
+// MoneyFromPrice parses p.Amount in p.CurrencyCode.
+func MoneyFromPrice(p *Price) (Money, error) {
+	if p == nil {
+		return Money{}, errors.New("amazonpay: missing price")
+	}
+	return ParseMoney(p.Amount, p.CurrencyCode)
+}
+
+// Price returns m as a Price.
+func (m Money) Price() *Price {
+	return &Price{Amount: m.Amount(), CurrencyCode: m.currency}
+}
+
+// Units returns m in minor units.
+func (m Money) Units() int64 { return m.units }
+
+// Currency returns the ISO 4217 currency code of m.
+func (m Money) Currency() string { return m.currency }
+
+// IsZero reports whether m is zero.
+func (m Money) IsZero() bool { return m.units == 0 }
+
+// IsNegative reports whether m is below zero.
+func (m Money) IsNegative() bool { return m.units < 0 }
+
+// Amount formats m with exactly the minor units of its currency, such as "10.50" or "1000".
+func (m Money) Amount() string {
+	digits := CurrencyMinorUnits[m.currency]
+	s := strconv.FormatUint(absUnits(m.units), 10)
+	if digits > 0 {
+		if len(s) <= digits {
+			s = strings.Repeat("0", digits-len(s)+1) + s
+		}
+		s = s[:len(s)-digits] + "." + s[len(s)-digits:]
+	}
+	if m.units < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+func (m Money) String() string {
+	return m.Amount() + " " + m.currency
+}
+
+// Add returns m + o.
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	sum := m.units + o.units
+	if (sum > m.units) != (o.units > 0) {
+		return Money{}, ErrAmountOverflow
+	}
+	return Money{units: sum, currency: m.currency}, nil
+}
+
+// Sub returns m - o.
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	diff := m.units - o.units
+	if (diff < m.units) != (o.units > 0) {
+		return Money{}, ErrAmountOverflow
+	}
+	return Money{units: diff, currency: m.currency}, nil
+}
+
+// Cmp returns -1, 0 or +1 when m is less than, equal to or greater than o.
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.units < o.units:
+		return -1, nil
+	case m.units > o.units:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Percent returns percent percent of m, such as "8" or "2.5", rounded with mode.
+func (m Money) Percent(percent string, mode RoundingMode) (Money, error) {
+	p, err := parseDecimal(percent)
+	if err != nil {
+		return Money{}, err
+	}
+	digits, err := minorUnits(m.currency)
+	if err != nil {
+		return Money{}, err
+	}
+	r := new(big.Rat).SetInt64(m.units)
+	r.Mul(r, p)
+	r.Quo(r, new(big.Rat).SetInt(new(big.Int).Mul(big.NewInt(100), pow10(digits))))
+	return roundMoney(r, m.currency, mode)
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.currency != o.currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, o.currency)
+	}
+	return nil
+}
+
+func minorUnits(currency string) (int, error) {
+	digits, ok := CurrencyMinorUnits[currency]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+	}
+	return digits, nil
+}
+
+// parseDecimal parses an optionally signed decimal number without exponent.
+func parseDecimal(s string) (*big.Rat, error) {
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	intPart, fracPart, _ := strings.Cut(digits, ".")
+	if intPart == "" && fracPart == "" || strings.Trim(intPart+fracPart, "0123456789") != "" {
+		return nil, fmt.Errorf("amazonpay: invalid amount %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("amazonpay: invalid amount %q", s)
+	}
+	return r, nil
+}
+
+func roundMoney(r *big.Rat, currency string, mode RoundingMode) (Money, error) {
+	digits, err := minorUnits(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(digits)))
+	q, rem := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		// Compare twice the remainder with the denominator to find ties.
+		half := new(big.Int).Abs(rem)
+		half.Lsh(half, 1)
+		cmp := half.Cmp(scaled.Denom())
+		away := false
+		switch mode {
+		case RoundHalfUp:
+			away = cmp >= 0
+		case RoundHalfEven:
+			away = cmp > 0 || cmp == 0 && q.Bit(0) == 1
+		case RoundDown:
+		case RoundUp:
+			away = true
+		}
+		if away {
+			q.Add(q, big.NewInt(int64(scaled.Sign())))
+		}
+	}
+	return moneyFromInt(q, currency)
+}
+
+func moneyFromInt(units *big.Int, currency string) (Money, error) {
+	if !units.IsInt64() {
+		return Money{}, ErrAmountOverflow
+	}
+	return Money{units: units.Int64(), currency: currency}, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func absUnits(units int64) uint64 {
+	if units == math.MinInt64 {
+		return uint64(math.MaxInt64) + 1
+	}
+	if units < 0 {
+		return uint64(-units)
+	}
+	return uint64(units)
+}
@@ -0,0 +1,260 @@
+package amazonpay
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// errInvalid stands for the errors without a sentinel, such as those of malformed amounts.
+var errInvalid = errors.New("invalid")
+
+func checkMoneyErr(t *testing.T, err, want error) {
+	t.Helper()
+	switch {
+	case want == nil && err != nil:
+		t.Fatalf("unexpected error %v", err)
+	case want == errInvalid && err == nil, want != nil && want != errInvalid && !errors.Is(err, want):
+		t.Fatalf("error %v, want %v", err, want)
+	}
+}
+
+func mustMoney(t *testing.T, units int64, currency string) Money {
+	t.Helper()
+	m, err := NewMoney(units, currency)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestParseMoney(t *testing.T) {
+	for _, tt := range []struct {
+		amount   string
+		currency string
+		want     int64
+		wantErr  error
+	}{
+		{"10.50", "USD", 1050, nil},
+		{"10.500", "USD", 1050, nil},
+		{"0.05", "USD", 5, nil},
+		{"-0.05", "USD", -5, nil},
+		{"+1", "USD", 100, nil},
+		{".5", "USD", 50, nil},
+		{"1000", "JPY", 1000, nil},
+		{"1000.0", "JPY", 1000, nil},
+		{"92233720368547758.07", "USD", math.MaxInt64, nil},
+		{"-92233720368547758.08", "USD", math.MinInt64, nil},
+		{"10.505", "USD", 0, ErrPrecision},
+		{"0.001", "USD", 0, ErrPrecision},
+		{"1000.5", "JPY", 0, ErrPrecision},
+		{"0.1", "JPY", 0, ErrPrecision},
+		{"92233720368547758.08", "USD", 0, ErrAmountOverflow},
+		{"1", "XXX", 0, ErrUnknownCurrency},
+		{"1", "usd", 0, ErrUnknownCurrency},
+		{"1e3", "USD", 0, errInvalid},
+		{".", "USD", 0, errInvalid},
+		{"+-1", "USD", 0, errInvalid},
+		{"-+1", "USD", 0, errInvalid},
+		{"", "USD", 0, errInvalid},
+		{"-", "USD", 0, errInvalid},
+		{"1,000", "USD", 0, errInvalid},
+		{" 1", "USD", 0, errInvalid},
+		{"1/2", "USD", 0, errInvalid},
+		{"0x10", "USD", 0, errInvalid},
+	} {
+		t.Run(tt.amount+" "+tt.currency, func(t *testing.T) {
+			m, err := ParseMoney(tt.amount, tt.currency)
+			checkMoneyErr(t, err, tt.wantErr)
+			if err == nil && (m.Units() != tt.want || m.Currency() != tt.currency) {
+				t.Errorf("ParseMoney = %d %s, want %d %s", m.Units(), m.Currency(), tt.want, tt.currency)
+			}
+		})
+	}
+}
+
+func TestRoundMoney(t *testing.T) {
+	modes := []RoundingMode{RoundHalfUp, RoundHalfEven, RoundDown, RoundUp}
+	for _, tt := range []struct {
+		amount   string
+		currency string
+		// want is indexed by modes.
+		want [4]int64
+	}{
+		{"0.12", "USD", [4]int64{12, 12, 12, 12}},
+		{"0.125", "USD", [4]int64{13, 12, 12, 13}},
+		{"0.135", "USD", [4]int64{14, 14, 13, 14}},
+		{"-0.125", "USD", [4]int64{-13, -12, -12, -13}},
+		{"-0.135", "USD", [4]int64{-14, -14, -13, -14}},
+		{"0.1251", "USD", [4]int64{13, 13, 12, 13}},
+		{"-0.1249", "USD", [4]int64{-12, -12, -12, -13}},
+		{"0.004", "USD", [4]int64{0, 0, 0, 1}},
+		{"0.005", "USD", [4]int64{1, 0, 0, 1}},
+		{"-0.005", "USD", [4]int64{-1, 0, 0, -1}},
+		{"2.5", "JPY", [4]int64{3, 2, 2, 3}},
+		{"3.5", "JPY", [4]int64{4, 4, 3, 4}},
+		{"-2.5", "JPY", [4]int64{-3, -2, -2, -3}},
+		{"-3.5", "JPY", [4]int64{-4, -4, -3, -4}},
+		{"2.4999999999999999999", "JPY", [4]int64{2, 2, 2, 3}},
+	} {
+		for i, mode := range modes {
+			m, err := RoundMoney(tt.amount, tt.currency, mode)
+			if err != nil {
+				t.Fatalf("RoundMoney(%s %s, %d): %v", tt.amount, tt.currency, mode, err)
+			}
+			if m.Units() != tt.want[i] {
+				t.Errorf("RoundMoney(%s %s, %d) = %d, want %d", tt.amount, tt.currency, mode, m.Units(), tt.want[i])
+			}
+		}
+	}
+
+	for _, tt := range []struct {
+		amount   string
+		currency string
+		wantErr  error
+	}{
+		{"92233720368547758.075", "USD", ErrAmountOverflow},
+		{"1", "XXX", ErrUnknownCurrency},
+		{"1e3", "USD", errInvalid},
+		{".", "USD", errInvalid},
+		{"+-1", "USD", errInvalid},
+	} {
+		_, err := RoundMoney(tt.amount, tt.currency, RoundHalfUp)
+		if tt.wantErr == errInvalid && err == nil || tt.wantErr != errInvalid && !errors.Is(err, tt.wantErr) {
+			t.Errorf("RoundMoney(%s %s) error %v, want %v", tt.amount, tt.currency, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMoneyAmount(t *testing.T) {
+	for _, tt := range []struct {
+		units    int64
+		currency string
+		want     string
+	}{
+		{0, "USD", "0.00"},
+		{5, "USD", "0.05"},
+		{-5, "USD", "-0.05"},
+		{50, "USD", "0.50"},
+		{1050, "USD", "10.50"},
+		{-1050, "USD", "-10.50"},
+		{0, "JPY", "0"},
+		{1000, "JPY", "1000"},
+		{-1000, "JPY", "-1000"},
+		{math.MaxInt64, "USD", "92233720368547758.07"},
+		{math.MinInt64, "USD", "-92233720368547758.08"},
+		{math.MinInt64, "JPY", "-9223372036854775808"},
+	} {
+		m := mustMoney(t, tt.units, tt.currency)
+		if got := m.Amount(); got != tt.want {
+			t.Errorf("Amount of %d %s = %q, want %q", tt.units, tt.currency, got, tt.want)
+		}
+		back, err := MoneyFromPrice(m.Price())
+		if err != nil || back != m {
+			t.Errorf("MoneyFromPrice(%v.Price()) = %v, %v", m, back, err)
+		}
+	}
+	if got := mustMoney(t, 5, "USD").String(); got != "0.05 USD" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestMoneyPercent(t *testing.T) {
+	for _, tt := range []struct {
+		units    int64
+		currency string
+		percent  string
+		mode     RoundingMode
+		want     int64
+		wantErr  error
+	}{
+		{1000, "JPY", "8", RoundHalfUp, 80, nil},
+		{1005, "JPY", "8", RoundHalfUp, 80, nil},
+		{1005, "JPY", "8", RoundUp, 81, nil},
+		{125, "JPY", "10", RoundHalfUp, 13, nil},
+		{125, "JPY", "10", RoundHalfEven, 12, nil},
+		{-125, "JPY", "10", RoundHalfUp, -13, nil},
+		{-125, "JPY", "10", RoundHalfEven, -12, nil},
+		{-125, "JPY", "10", RoundDown, -12, nil},
+		{1050, "USD", "2.5", RoundHalfUp, 26, nil},
+		{1050, "USD", "2.5", RoundUp, 27, nil},
+		{1050, "USD", "2.5", RoundDown, 26, nil},
+		{5, "USD", "50", RoundHalfEven, 2, nil},
+		{5, "USD", "50", RoundHalfUp, 3, nil},
+		{math.MaxInt64, "USD", "200", RoundHalfUp, 0, ErrAmountOverflow},
+		{1000, "JPY", "1e1", RoundHalfUp, 0, errInvalid},
+		{1000, "JPY", "", RoundHalfUp, 0, errInvalid},
+	} {
+		m := mustMoney(t, tt.units, tt.currency)
+		got, err := m.Percent(tt.percent, tt.mode)
+		if tt.wantErr != nil {
+			if tt.wantErr == errInvalid && err == nil || tt.wantErr != errInvalid && !errors.Is(err, tt.wantErr) {
+				t.Errorf("%v.Percent(%q) error %v, want %v", m, tt.percent, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got.Units() != tt.want || got.Currency() != tt.currency {
+			t.Errorf("%v.Percent(%q, %d) = %d %s, %v, want %d", m, tt.percent, tt.mode, got.Units(), got.Currency(), err, tt.want)
+		}
+	}
+	if _, err := (Money{}).Percent("10", RoundHalfUp); !errors.Is(err, ErrUnknownCurrency) {
+		t.Errorf("Percent of the zero Money: %v, want ErrUnknownCurrency", err)
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	usd := func(units int64) Money { return mustMoney(t, units, "USD") }
+	for _, tt := range []struct {
+		name    string
+		op      func(Money, Money) (Money, error)
+		a, b    Money
+		want    int64
+		wantErr error
+	}{
+		{"add", Money.Add, usd(1050), usd(5), 1055, nil},
+		{"add negative", Money.Add, usd(5), usd(-10), -5, nil},
+		{"add max", Money.Add, usd(math.MaxInt64 - 1), usd(1), math.MaxInt64, nil},
+		{"add overflow", Money.Add, usd(math.MaxInt64), usd(1), 0, ErrAmountOverflow},
+		{"add underflow", Money.Add, usd(math.MinInt64), usd(-1), 0, ErrAmountOverflow},
+		{"add min", Money.Add, usd(math.MinInt64), usd(math.MaxInt64), -1, nil},
+		{"add mismatch", Money.Add, usd(1), mustMoney(t, 1, "JPY"), 0, ErrCurrencyMismatch},
+		{"add zero value", Money.Add, usd(1), Money{}, 0, ErrCurrencyMismatch},
+		{"sub", Money.Sub, usd(1050), usd(5), 1045, nil},
+		{"sub below zero", Money.Sub, usd(5), usd(10), -5, nil},
+		{"sub overflow", Money.Sub, usd(math.MaxInt64), usd(-1), 0, ErrAmountOverflow},
+		{"sub underflow", Money.Sub, usd(math.MinInt64), usd(1), 0, ErrAmountOverflow},
+		{"sub min from zero", Money.Sub, usd(0), usd(math.MinInt64), 0, ErrAmountOverflow},
+		{"sub min from minus one", Money.Sub, usd(-1), usd(math.MinInt64), math.MaxInt64, nil},
+		{"sub mismatch", Money.Sub, mustMoney(t, 1, "EUR"), usd(1), 0, ErrCurrencyMismatch},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.op(tt.a, tt.b)
+			checkMoneyErr(t, err, tt.wantErr)
+			if err == nil && (got.Units() != tt.want || got.Currency() != "USD") {
+				t.Errorf("got %d %s, want %d USD", got.Units(), got.Currency(), tt.want)
+			}
+		})
+	}
+
+	if _, err := usd(1).Cmp(mustMoney(t, 1, "JPY")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Cmp of USD and JPY: %v, want ErrCurrencyMismatch", err)
+	}
+	for _, tt := range []struct {
+		a, b Money
+		want int
+	}{
+		{usd(1), usd(2), -1},
+		{usd(2), usd(2), 0},
+		{usd(math.MaxInt64), usd(math.MinInt64), 1},
+	} {
+		if got, err := tt.a.Cmp(tt.b); err != nil || got != tt.want {
+			t.Errorf("%v.Cmp(%v) = %d, %v, want %d", tt.a, tt.b, got, err, tt.want)
+		}
+	}
+	if _, err := NewMoney(1, "XXX"); !errors.Is(err, ErrUnknownCurrency) {
+		t.Errorf("NewMoney in XXX: %v, want ErrUnknownCurrency", err)
+	}
+	if _, err := MoneyFromPrice(nil); err == nil {
+		t.Error("MoneyFromPrice(nil) succeeded")
+	}
+}
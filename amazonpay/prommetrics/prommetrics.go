@@ -0,0 +1,65 @@
+// Package prommetrics provides a Prometheus implementation of amazonpay.Metrics.
+package prommetrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay"
+)
+
+const namespace = "amazonpay"
+
+var _ amazonpay.Metrics = (*Metrics)(nil)
+
+// Metrics records amazonpay client measurements as Prometheus collectors.
+type Metrics struct {
+	requests       *prometheus.CounterVec
+	duration       *prometheus.HistogramVec
+	chargeOutcomes *prometheus.CounterVec
+}
+
+// New creates the collectors and registers them to reg.
+func New(reg prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Total number of Amazon Pay API requests.",
+		}, []string{"operation", "region", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of Amazon Pay API requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "region", "status"}),
+		chargeOutcomes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "charge_outcomes_total",
+			Help:      "Total number of charges created or captured by state and reason code.",
+		}, []string{"operation", "region", "state", "reason_code"}),
+	}
+	for _, c := range []prometheus.Collector{m.requests, m.duration, m.chargeOutcomes} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ObserveRequest implements amazonpay.Metrics.
+func (m *Metrics) ObserveRequest(operation, region string, statusCode int, duration time.Duration) {
+	status := "error"
+	if statusCode != 0 {
+		status = strconv.Itoa(statusCode)
+	}
+	m.requests.WithLabelValues(operation, region, status).Inc()
+	m.duration.WithLabelValues(operation, region, status).Observe(duration.Seconds())
+}
+
+// ObserveChargeOutcome implements amazonpay.Metrics.
+func (m *Metrics) ObserveChargeOutcome(operation, region, state, reasonCode string) {
+	m.chargeOutcomes.WithLabelValues(operation, region, state, reasonCode).Inc()
+}
@@ -0,0 +1,99 @@
+package prommetrics_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/prommetrics"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	m, err := prommetrics.New(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prommetrics.New(reg); err == nil {
+		t.Error("registered the collectors twice")
+	}
+
+	m.ObserveRequest("GetCharge", "jp", http.StatusOK, 20*time.Millisecond)
+	m.ObserveRequest("GetCharge", "jp", http.StatusOK, 30*time.Millisecond)
+	m.ObserveRequest("GetCharge", "jp", 0, time.Second)
+	m.ObserveChargeOutcome("CreateCharge", "jp", "Declined", "HardDeclined")
+
+	want := `
+# HELP amazonpay_charge_outcomes_total Total number of charges created or captured by state and reason code.
+# TYPE amazonpay_charge_outcomes_total counter
+amazonpay_charge_outcomes_total{operation="CreateCharge",reason_code="HardDeclined",region="jp",state="Declined"} 1
+# HELP amazonpay_requests_total Total number of Amazon Pay API requests.
+# TYPE amazonpay_requests_total counter
+amazonpay_requests_total{operation="GetCharge",region="jp",status="200"} 2
+amazonpay_requests_total{operation="GetCharge",region="jp",status="error"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "amazonpay_requests_total", "amazonpay_charge_outcomes_total"); err != nil {
+		t.Error(err)
+	}
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if f.GetName() == "amazonpay_request_duration_seconds" && len(f.GetMetric()) != 2 {
+			t.Errorf("%d request duration series, want 2", len(f.GetMetric()))
+		}
+	}
+}
+
+func TestClientMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := prommetrics.New(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		body := `{"chargeId":"S03-1","statusDetails":{"state":"Authorized"}}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})
+	c, err := amazonpay.NewWithSigner(signingtest.PublicKeyID, signingtest.Signer(), "jp", true, &http.Client{Transport: rt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Metrics = m
+
+	ctx := context.Background()
+	if _, _, err := c.CreateCharge(ctx, &amazonpay.CreateChargeRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, _, err := c.GetCharge(ctx, "S03-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := `
+# HELP amazonpay_charge_outcomes_total Total number of charges created or captured by state and reason code.
+# TYPE amazonpay_charge_outcomes_total counter
+amazonpay_charge_outcomes_total{operation="CreateCharge",reason_code="",region="jp",state="Authorized"} 1
+# HELP amazonpay_requests_total Total number of Amazon Pay API requests.
+# TYPE amazonpay_requests_total counter
+amazonpay_requests_total{operation="CreateCharge",region="jp",status="200"} 1
+amazonpay_requests_total{operation="GetCharge",region="jp",status="200"} 5
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "amazonpay_requests_total", "amazonpay_charge_outcomes_total"); err != nil {
+		t.Error(err)
+	}
+}
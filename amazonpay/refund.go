@@ -2,6 +2,7 @@ package amazonpay
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 )
@@ -12,26 +13,40 @@ type CreateRefundRequest struct {
 	SoftDescriptor string `json:"softDescriptor,omitempty"`
 }
 
-type RefundResponse struct {
+// Refund is a refund as returned by CreateRefund and GetRefund.
+type Refund struct {
 	ErrorResponse
 	RefundID           string         `json:"refundId,omitempty"`
 	ChargeID           string         `json:"chargeId,omitempty"`
 	RefundAmount       *Price         `json:"refundAmount,omitempty"`
 	SoftDescriptor     string         `json:"softDescriptor,omitempty"`
-	CreationTimestamp  string         `json:"creationTimestamp,omitempty"`
+	CreationTimestamp  Timestamp      `json:"creationTimestamp,omitempty"`
 	StatusDetails      *StatusDetails `json:"statusDetails,omitempty"`
 	ReleaseEnvironment string         `json:"releaseEnvironment,omitempty"`
+	// Extra holds the top level fields of the response not modeled above, as received.
+	// They are written back by MarshalJSON, which also keeps the nested fields of the members left unchanged.
+	Extra map[string]json.RawMessage `json:"-"`
+
+	raw json.RawMessage
 }
 
-type CreateRefundResponse RefundResponse
+// RefundResponse is kept for compatibility, it is a Refund.
+type RefundResponse = Refund
+
+// CreateRefundResponse is kept for compatibility, it is a Refund.
+type CreateRefundResponse = Refund
 
-func (c *Client) CreateRefund(ctx context.Context, req *CreateRefundRequest) (*CreateRefundResponse, *http.Response, error) {
+func (c *Client) CreateRefund(ctx context.Context, req *CreateRefundRequest) (*Refund, *http.Response, error) {
+	ctx = withOperation(ctx, "CreateRefund")
+	if err := c.validate(req); err != nil {
+		return nil, nil, err
+	}
 	path := fmt.Sprintf("%s/refunds", APIVersion)
-	httpReq, err := c.NewRequest(http.MethodPost, path, req)
+	httpReq, err := c.newRequest(http.MethodPost, path, req)
 	if err != nil {
 		return nil, nil, err
 	}
-	resp := new(CreateRefundResponse)
+	resp := new(Refund)
 	httpResp, err := c.Do(ctx, httpReq, resp)
 	if err != nil {
 		return nil, httpResp, err
@@ -39,15 +54,17 @@ func (c *Client) CreateRefund(ctx context.Context, req *CreateRefundRequest) (*C
 	return resp, httpResp, nil
 }
 
-type GetRefundResponse RefundResponse
+// GetRefundResponse is kept for compatibility, it is a Refund.
+type GetRefundResponse = Refund
 
-func (c *Client) GetRefund(ctx context.Context, refundID string) (*GetRefundResponse, *http.Response, error) {
+func (c *Client) GetRefund(ctx context.Context, refundID string) (*Refund, *http.Response, error) {
+	ctx = withOperation(ctx, "GetRefund")
 	path := fmt.Sprintf("%s/refunds/%s", APIVersion, refundID)
-	httpReq, err := c.NewRequest(http.MethodGet, path, nil)
+	httpReq, err := c.newRequest(http.MethodGet, path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
-	resp := new(GetRefundResponse)
+	resp := new(Refund)
 	httpResp, err := c.Do(ctx, httpReq, resp)
 	if err != nil {
 		return nil, httpResp, err
@@ -0,0 +1,43 @@
+package signing
+
+import "crypto/rsa"
+
+// SignatureAlgorithm is an Amazon Pay signature algorithm.
+type SignatureAlgorithm interface {
+	// Name is the label written in the string to sign and the Authorization header.
+	Name() string
+	// SaltLength is the RSASSA-PSS salt length in bytes.
+	SaltLength() int
+}
+
+type pssAlgorithm struct {
+	name       string
+	saltLength int
+}
+
+func (a pssAlgorithm) Name() string    { return a.name }
+func (a pssAlgorithm) SaltLength() int { return a.saltLength }
+
+var (
+	// AlgorithmV1 is AMZN-PAY-RSASSA-PSS with a 20 byte salt.
+	AlgorithmV1 SignatureAlgorithm = pssAlgorithm{name: Algorithm, saltLength: 20}
+	// AlgorithmV2 is AMZN-PAY-RSASSA-PSS-V2 with a 32 byte salt.
+	AlgorithmV2 SignatureAlgorithm = pssAlgorithm{name: AlgorithmNameV2, saltLength: 32}
+)
+
+// AlgorithmByName returns the algorithm labeled name.
+func AlgorithmByName(name string) (SignatureAlgorithm, bool) {
+	for _, alg := range []SignatureAlgorithm{AlgorithmV1, AlgorithmV2} {
+		if alg.Name() == name {
+			return alg, true
+		}
+	}
+	return nil, false
+}
+
+func pssOptions(alg SignatureAlgorithm) *rsa.PSSOptions {
+	return &rsa.PSSOptions{
+		SaltLength: alg.SaltLength(),
+		Hash:       hashFunc,
+	}
+}
@@ -0,0 +1,307 @@
+package signing_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+// The ref* functions are a reference canonicalizer written from the specification,
+// independently of the buffered implementation of package signing.
+
+// refEscape percent-encodes every byte except the RFC 3986 unreserved characters.
+func refEscape(s string) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hexDigits[c>>4])
+			b.WriteByte(hexDigits[c&15])
+		}
+	}
+	return b.String()
+}
+
+// refCanonicalURI removes empty and dot segments, resolves ".." and escapes each segment.
+func refCanonicalURI(path string) string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		switch segment {
+		case "", ".":
+		case "..":
+			if len(segments) > 0 {
+				segments = segments[:len(segments)-1]
+			}
+		default:
+			segments = append(segments, refEscape(segment))
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// refCanonicalQueryString sorts the escaped "key=value" pairs, writing "key" alone for empty values.
+func refCanonicalQueryString(query url.Values) string {
+	var pairs []string
+	for key, values := range query {
+		for _, value := range values {
+			if value == "" {
+				pairs = append(pairs, refEscape(key))
+			} else {
+				pairs = append(pairs, refEscape(key)+"="+refEscape(value))
+			}
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// refTrim trims the value and collapses runs of spaces outside double quotes.
+func refTrim(value string) string {
+	var b strings.Builder
+	quoted := false
+	for i, c := range []byte(strings.TrimSpace(value)) {
+		if c == '"' {
+			quoted = !quoted
+		}
+		if c == ' ' && !quoted && i > 0 && b.String()[b.Len()-1] == ' ' {
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// refHeaders returns the values of every header of h by lower case name, Authorization excluded.
+func refHeaders(h http.Header) map[string][]string {
+	headers := map[string][]string{}
+	for key, values := range h {
+		name := strings.ToLower(key)
+		if name != "authorization" {
+			headers[name] = append(headers[name], values...)
+		}
+	}
+	return headers
+}
+
+func refSignedHeaders(h http.Header) string {
+	var names []string
+	for name := range refHeaders(h) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ";")
+}
+
+// refCanonicalHeaders writes a sorted "name:value,value" line per header with values, or a newline without any.
+func refCanonicalHeaders(h http.Header) string {
+	var lines []string
+	for name, values := range refHeaders(h) {
+		if len(values) == 0 {
+			continue
+		}
+		sorted := append([]string(nil), values...)
+		sort.Strings(sorted)
+		trimmed := make([]string, len(sorted))
+		for i, v := range sorted {
+			trimmed[i] = refTrim(v)
+		}
+		lines = append(lines, name+":"+strings.Join(trimmed, ","))
+	}
+	if len(lines) == 0 {
+		return "\n"
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func refCanonicalRequest(method string, u *url.URL, h http.Header, body string) string {
+	hash := sha256.Sum256([]byte(body))
+	return strings.Join([]string{
+		method,
+		refCanonicalURI(u.Path),
+		refCanonicalQueryString(u.Query()),
+		refCanonicalHeaders(h),
+		refSignedHeaders(h),
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+}
+
+// encodeHeader and decodeHeader map a header to a fuzzable "key:value" line per value.
+// Keys are kept as is, so that non-canonical keys are exercised.
+func encodeHeader(h http.Header) string {
+	var lines []string
+	for key, values := range h {
+		for _, v := range values {
+			lines = append(lines, key+":"+v)
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+func decodeHeader(s string) http.Header {
+	h := http.Header{}
+	for _, line := range strings.Split(s, "\n") {
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			h[key] = append(h[key], value)
+		}
+	}
+	return h
+}
+
+func vectors(f *testing.F) []signingtest.Vector {
+	f.Helper()
+	vectors, err := signingtest.Vectors()
+	if err != nil {
+		f.Fatal(err)
+	}
+	return vectors
+}
+
+// newFuzzRequest skips the inputs that net/http would not send.
+func newFuzzRequest(t *testing.T, method, rawURL, header, body string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(method, rawURL, strings.NewReader(body)) //nolint:noctx // test
+	if err != nil {
+		t.Skip(err)
+	}
+	req.Header = decodeHeader(header)
+	for key := range req.Header {
+		if key == "" || strings.IndexFunc(key, func(r rune) bool {
+			return r > '~' || r <= ' ' || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r)
+		}) >= 0 {
+			t.Skipf("invalid header name %q", key)
+		}
+	}
+	return req
+}
+
+func checkInvariants(t *testing.T, req *http.Request) {
+	t.Helper()
+	if err := signingtest.CheckInvariants(req); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func FuzzCanonicalURI(f *testing.F) {
+	for _, v := range vectors(f) {
+		u, err := url.Parse(v.URL)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(u.Path)
+	}
+	f.Fuzz(func(t *testing.T, path string) {
+		req := &http.Request{Method: http.MethodGet, URL: &url.URL{Scheme: "https", Host: "pay-api.amazon.jp", Path: path}, Header: http.Header{}}
+		uri := signing.CanonicalURI(req)
+		if want := refCanonicalURI(path); uri != want {
+			t.Fatalf("CanonicalURI(%q) = %q, reference %q", path, uri, want)
+		}
+		if req.URL.Path != path {
+			t.Fatalf("CanonicalURI modified the path to %q", req.URL.Path)
+		}
+		again, err := url.PathUnescape(uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.URL.Path = again
+		if got := signing.CanonicalURI(req); got != uri {
+			t.Fatalf("CanonicalURI is not idempotent: %q then %q", uri, got)
+		}
+		checkInvariants(t, req)
+	})
+}
+
+func FuzzCanonicalQueryString(f *testing.F) {
+	for _, v := range vectors(f) {
+		u, err := url.Parse(v.URL)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(u.RawQuery)
+	}
+	f.Add("=&0")
+	f.Fuzz(func(t *testing.T, rawQuery string) {
+		req := &http.Request{Method: http.MethodGet, URL: &url.URL{Scheme: "https", Host: "pay-api.amazon.jp", Path: "/v2/reports", RawQuery: rawQuery}, Header: http.Header{}}
+		query := signing.CanonicalQueryString(req)
+		if want := refCanonicalQueryString(req.URL.Query()); query != want {
+			t.Fatalf("CanonicalQueryString(%q) = %q, reference %q", rawQuery, query, want)
+		}
+		// A lone "=" is an empty pair, which sorts first and is lost when the query is parsed again.
+		if query == "" || strings.HasPrefix(query, "&") {
+			checkInvariants(t, req)
+			return
+		}
+		req.URL.RawQuery = query
+		if got := signing.CanonicalQueryString(req); got != query {
+			t.Fatalf("CanonicalQueryString is not idempotent: %q then %q", query, got)
+		}
+		checkInvariants(t, req)
+	})
+}
+
+func FuzzCanonicalHeaders(f *testing.F) {
+	for _, v := range vectors(f) {
+		f.Add(encodeHeader(v.Header))
+	}
+	f.Add(",:")
+	f.Fuzz(func(t *testing.T, header string) {
+		req := newFuzzRequest(t, http.MethodGet, "https://pay-api.amazon.jp/v2/charges/S03-1", header, "")
+		headers := signing.CanonicalHeaders(req)
+		if want := refCanonicalHeaders(req.Header); headers != want {
+			t.Fatalf("CanonicalHeaders(%q) = %q, reference %q", header, headers, want)
+		}
+		if got, want := signing.SignedHeaders(req), refSignedHeaders(req.Header); got != want {
+			t.Fatalf("SignedHeaders(%q) = %q, reference %q", header, got, want)
+		}
+		// Canonicalizing lower case keys with canonical values changes nothing.
+		canonical := http.Header{}
+		for key, values := range req.Header {
+			name := strings.ToLower(key)
+			for _, v := range values {
+				canonical[name] = append(canonical[name], refTrim(v))
+			}
+		}
+		req.Header = canonical
+		if got := signing.CanonicalHeaders(req); got != headers {
+			t.Fatalf("CanonicalHeaders is not idempotent: %q then %q", headers, got)
+		}
+		checkInvariants(t, req)
+	})
+}
+
+func FuzzSign(f *testing.F) {
+	for _, v := range vectors(f) {
+		f.Add(v.Method, v.URL, encodeHeader(v.Header), v.Body)
+	}
+	f.Fuzz(func(t *testing.T, method, rawURL, header, body string) {
+		req := newFuzzRequest(t, method, rawURL, header, body)
+		canonicalRequest, err := signing.CanonicalRequest(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := refCanonicalRequest(req.Method, req.URL, req.Header, body); canonicalRequest != want {
+			t.Fatalf("CanonicalRequest = %q, reference %q", canonicalRequest, want)
+		}
+		again, err := signing.CanonicalRequest(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again != canonicalRequest {
+			t.Fatalf("CanonicalRequest is not stable: %q then %q", canonicalRequest, again)
+		}
+		checkInvariants(t, req)
+	})
+}
@@ -0,0 +1,136 @@
+package signing
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Diagnostics holds what was signed for a request.
+// It does not contain the private key, the signature or the request payload,
+// and the values of sensitive headers in CanonicalRequest are replaced with Redacted.
+type Diagnostics struct {
+	CanonicalRequest string
+	StringToSign     string
+	SignedHeaders    string
+}
+
+// Redacted replaces the values of sensitive headers in Diagnostics.CanonicalRequest.
+const Redacted = "[REDACTED]"
+
+// sensitiveHeaders are headers whose values are redacted from diagnostics.
+var sensitiveHeaders = map[string]bool{
+	"authorization":        true,
+	"cookie":               true,
+	"proxy-authorization":  true,
+	"set-cookie":           true,
+	"x-amz-security-token": true,
+}
+
+// IsSensitiveHeader reports whether the value of the header name is redacted from diagnostics:
+// credentials, cookies and headers whose name contains "token", "secret", "password", "api-key" or "apikey".
+func IsSensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	if sensitiveHeaders[name] {
+		return true
+	}
+	for _, s := range []string{"token", "secret", "password", "api-key", "apikey"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewDiagnostics builds the diagnostics of a signed request, redacting sensitive header values.
+func NewDiagnostics(canonicalRequest, stringToSign, signedHeaders string) *Diagnostics {
+	return &Diagnostics{
+		CanonicalRequest: redactCanonicalRequest(canonicalRequest),
+		StringToSign:     stringToSign,
+		SignedHeaders:    signedHeaders,
+	}
+}
+
+// redactCanonicalRequest replaces the values of sensitive headers in the header lines of a canonical request,
+// the lines between the query string and the blank line before the signed headers.
+func redactCanonicalRequest(canonicalRequest string) string {
+	lines := strings.Split(canonicalRequest, "\n")
+	redacted := false
+	for i := 3; i < len(lines) && lines[i] != ""; i++ {
+		name, _, ok := strings.Cut(lines[i], ":")
+		if ok && IsSensitiveHeader(name) {
+			lines[i] = name + ":" + Redacted
+			redacted = true
+		}
+	}
+	if !redacted {
+		return canonicalRequest
+	}
+	return strings.Join(lines, "\n")
+}
+
+// WireCanonicalRequest rebuilds the canonical request of r as it was sent on the wire,
+// using only the headers listed in signedHeaders.
+func WireCanonicalRequest(r *http.Request, signedHeaders string) (string, error) {
+	var names []string
+	for _, name := range strings.Split(signedHeaders, ";") {
+		if name != "authorization" {
+			names = append(names, name)
+		}
+	}
+	return CanonicalRequestWithHeaders(r, names)
+}
+
+// Diff compares the signed canonical request with the one rebuilt from the wire request r,
+// both with sensitive header values redacted.
+// Lines only in the signed request are prefixed with "-", lines only on the wire with "+".
+// It returns nil when both are identical.
+func (d *Diagnostics) Diff(r *http.Request) ([]string, error) {
+	wire, err := WireCanonicalRequest(r, d.SignedHeaders)
+	if err != nil {
+		return nil, err
+	}
+	wire = redactCanonicalRequest(wire)
+	if wire == d.CanonicalRequest {
+		return nil, nil
+	}
+	return diffLines(strings.Split(d.CanonicalRequest, "\n"), strings.Split(wire, "\n")), nil
+}
+
+// diffLines returns a line diff of a and b based on their longest common subsequence.
+func diffLines(a, b []string) []string {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var diff []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, "-"+a[i])
+			i++
+		default:
+			diff = append(diff, "+"+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, "-"+a[i])
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, "+"+b[j])
+	}
+	return diff
+}
@@ -0,0 +1,62 @@
+package signing_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+func TestDiagnosticsRedactsSensitiveHeaders(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://pay-api.amazon.jp/v2/charges/S03-1", nil) //nolint:noctx // test
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := map[string]string{
+		"Cookie":              "session=s3cr3t-cookie",
+		"Proxy-Authorization": "Basic s3cr3t-proxy",
+		"X-Api-Key":           "s3cr3t-api-key",
+		"X-Auth-Token":        "s3cr3t-token",
+	}
+	for name, value := range secrets {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("X-Custom", "visible")
+
+	var d *signing.Diagnostics
+	transport := &signing.Transport{
+		PublicKeyID: signingtest.PublicKeyID,
+		Signer:      signingtest.Signer(),
+		Region:      "jp",
+		OnSigned:    func(_ *http.Request, got *signing.Diagnostics) { d = got },
+	}
+	signed, err := transport.Sign(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, value := range secrets {
+		if strings.Contains(d.CanonicalRequest, value) {
+			t.Errorf("canonical request contains the value of %s", name)
+		}
+		if line := strings.ToLower(name) + ":" + signing.Redacted; !strings.Contains(d.CanonicalRequest, line) {
+			t.Errorf("canonical request misses %q", line)
+		}
+	}
+	if !strings.Contains(d.CanonicalRequest, "x-custom:visible\n") {
+		t.Errorf("canonical request misses x-custom:\n%s", d.CanonicalRequest)
+	}
+	if !strings.Contains(d.SignedHeaders, "cookie") {
+		t.Errorf("signed headers %q miss cookie", d.SignedHeaders)
+	}
+
+	diff, err := d.Diff(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil {
+		t.Errorf("Diff of the signed request = %q, want nil", diff)
+	}
+}
@@ -0,0 +1,157 @@
+package signing
+
+import (
+	"bytes"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// RequiredHeaders are always signed when present on the request.
+var RequiredHeaders = []string{
+	"accept",
+	"content-type",
+	"x-amz-pay-date",
+	"x-amz-pay-host",
+	"x-amz-pay-idempotency-key",
+	"x-amz-pay-region",
+}
+
+// HeaderPolicy reports whether a header, other than RequiredHeaders, is signed.
+// name is lower case.
+type HeaderPolicy func(name string) bool
+
+// SignAllHeaders signs every header of the request.
+func SignAllHeaders(string) bool { return true }
+
+// SignRequiredHeaders signs RequiredHeaders only.
+func SignRequiredHeaders(string) bool { return false }
+
+// SignHeaders returns a policy signing names in addition to RequiredHeaders.
+func SignHeaders(names ...string) HeaderPolicy {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[strings.ToLower(name)] = true
+	}
+	return func(name string) bool { return set[name] }
+}
+
+func isRequiredHeader(name string) bool {
+	for _, required := range RequiredHeaders {
+		if name == required {
+			return true
+		}
+	}
+	return false
+}
+
+// SignedHeaderNames returns the sorted lower case names of the headers of r signed under policy.
+// A nil policy signs every header. Authorization is never signed.
+// Keys differing only in case, such as a non-canonical key set on the map directly, are listed once.
+func SignedHeaderNames(r *http.Request, policy HeaderPolicy) []string {
+	if policy == nil {
+		policy = SignAllHeaders
+	}
+	names := make([]string, 0, len(r.Header))
+	for key := range r.Header {
+		name := strings.ToLower(key)
+		if name == "authorization" {
+			continue
+		}
+		if isRequiredHeader(name) || policy(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	// Drop the duplicates of keys differing only in case, adjacent once sorted.
+	out := names[:0]
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// canonicalHeaders returns the canonical header lines of names, skipping names absent from h.
+func canonicalHeaders(h http.Header, names []string) string {
+	var buf bytes.Buffer
+	writeCanonicalHeaders(&buf, h, names)
+	return buf.String()
+}
+
+// writeCanonicalHeaders writes a "name:values\n" line for each of names present in h, or a single newline if none is.
+// Values of keys differing only in case are merged, h.Values would miss non-canonical keys.
+// Lines are sorted as whole strings, so "x-a-b:" comes before "x-a:".
+func writeCanonicalHeaders(buf *bytes.Buffer, h http.Header, names []string) {
+	ordered := make([]string, len(names))
+	copy(ordered, names)
+	sort.Slice(ordered, func(i, j int) bool { return lineLess(ordered[i], ordered[j]) })
+	written := false
+	for _, name := range ordered {
+		values := headerValues(h, name)
+		if len(values) == 0 {
+			continue
+		}
+		written = true
+		buf.WriteString(name)
+		buf.WriteByte(':')
+		for i, v := range values {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(trimString(v))
+		}
+		buf.WriteByte('\n')
+	}
+	if !written {
+		buf.WriteByte('\n')
+	}
+}
+
+// headerValues returns the sorted values of every key of h equal to name ignoring case.
+// The values are copied before sorting when needed, h is never modified.
+func headerValues(h http.Header, name string) []string {
+	var values []string
+	copied := false
+	for key, vs := range h {
+		if len(vs) == 0 || !strings.EqualFold(key, name) {
+			continue
+		}
+		if values == nil {
+			values = vs
+			continue
+		}
+		if !copied {
+			values = append([]string(nil), values...)
+			copied = true
+		}
+		values = append(values, vs...)
+	}
+	if len(values) > 1 && !sort.StringsAreSorted(values) {
+		if !copied {
+			values = append([]string(nil), values...)
+		}
+		sort.Strings(values)
+	}
+	return values
+}
+
+// lineLess reports whether a+":" sorts before b+":".
+func lineLess(a, b string) bool {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	if a[:n] != b[:n] {
+		return a[:n] < b[:n]
+	}
+	switch {
+	case len(a) == len(b):
+		return false
+	case len(a) < len(b):
+		return b[n] > ':'
+	default:
+		return a[n] < ':'
+	}
+}
@@ -0,0 +1,100 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// Generate returns a new RSA private key of bits, at least MinKeyBits.
+func Generate(bits int) (*rsa.PrivateKey, error) {
+	if bits < MinKeyBits {
+		return nil, fmt.Errorf("keys: RSA key size %d is below %d", bits, MinKeyBits)
+	}
+	return rsa.GenerateKey(rand.Reader, bits)
+}
+
+// EncodePKCS8 returns key as a PKCS#8 "PRIVATE KEY" PEM block, the format expected by amazonpay.New.
+func EncodePKCS8(key *rsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// EncodePKCS1 returns key as a PKCS#1 "RSA PRIVATE KEY" PEM block.
+func EncodePKCS1(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+// EncodeBase64 returns the PKCS#8 PEM of key encoded in base64, for environment variables.
+func EncodeBase64(key *rsa.PrivateKey) ([]byte, error) {
+	b, err := EncodePKCS8(key)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(base64.StdEncoding.EncodeToString(b)), nil
+}
+
+// EncodePublicKey returns the "PUBLIC KEY" PEM block uploaded to Seller Central.
+func EncodePublicKey(pub *rsa.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// Fingerprint returns "SHA256:" followed by the hex encoded SHA-256 hash of the DER encoded public key.
+func Fingerprint(pub *rsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return "SHA256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// ParsePublicKey parses an RSA public key from PKIX or PKCS#1 PEM, DER, or base64 wrapped PEM or DER.
+func ParsePublicKey(data []byte) (*rsa.PublicKey, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("keys: empty public key data")
+	}
+	if !bytes.HasPrefix(data, []byte("-----BEGIN")) {
+		if decoded, err := base64.StdEncoding.DecodeString(string(bytes.Join(bytes.Fields(data), nil))); err == nil {
+			data = bytes.TrimSpace(decoded)
+		}
+	}
+	der := data
+	if bytes.HasPrefix(data, []byte("-----BEGIN")) {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("keys: invalid PEM data")
+		}
+		if block.Type != "PUBLIC KEY" && block.Type != "RSA PUBLIC KEY" {
+			return nil, fmt.Errorf("keys: got %s, want a public key", block.Type)
+		}
+		der = block.Bytes
+	}
+	if pub, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		return pub, nil
+	}
+	pubIF, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("keys: invalid public key: %w", err)
+	}
+	pub, ok := pubIF.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("keys: got %T, want an RSA public key", pubIF)
+	}
+	return pub, nil
+}
@@ -0,0 +1,160 @@
+// Package keys loads RSA private keys for Amazon Pay from PEM, DER and base64 data,
+// files, environment variables and readers.
+//
+// Accepted formats are PKCS#8 and PKCS#1, passphrase protected PKCS#8 ("ENCRYPTED PRIVATE KEY")
+// and legacy encrypted PKCS#1 PEM, raw DER, and any of those wrapped in base64.
+// It also generates keys and encodes them back to those formats.
+package keys
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// MinKeyBits is the smallest accepted RSA key size.
+const MinKeyBits = 2048
+
+var (
+	// ErrPassphraseRequired is returned for an encrypted key loaded without a passphrase.
+	ErrPassphraseRequired = errors.New("keys: passphrase required for encrypted private key")
+	// ErrIncorrectPassphrase is returned when an encrypted key cannot be decrypted with the passphrase.
+	ErrIncorrectPassphrase = errors.New("keys: incorrect passphrase")
+	// ErrUnknownFormat is returned when the data is not a supported private key encoding.
+	ErrUnknownFormat = errors.New("keys: unknown private key format")
+)
+
+// Parse parses an RSA private key. passphrase is only used for encrypted keys and may be nil.
+func Parse(data, passphrase []byte) (*rsa.PrivateKey, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("keys: empty private key data")
+	}
+	if bytes.HasPrefix(data, []byte("-----BEGIN")) {
+		return parsePEM(data, passphrase)
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(string(bytes.Join(bytes.Fields(data), nil))); err == nil {
+		decoded = bytes.TrimSpace(decoded)
+		if bytes.HasPrefix(decoded, []byte("-----BEGIN")) {
+			return parsePEM(decoded, passphrase)
+		}
+		if key, err := parseDER(decoded, passphrase); err == nil || !errors.Is(err, ErrUnknownFormat) {
+			return key, err
+		}
+	}
+	return parseDER(data, passphrase)
+}
+
+// LoadFile reads and parses the private key file at path.
+func LoadFile(path string, passphrase []byte) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("keys: %w", err)
+	}
+	key, err := Parse(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// LoadEnv parses the private key held in the environment variable name.
+func LoadEnv(name string, passphrase []byte) (*rsa.PrivateKey, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return nil, fmt.Errorf("keys: environment variable %s is not set", name)
+	}
+	key, err := Parse([]byte(value), passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return key, nil
+}
+
+// Load reads and parses a private key from r.
+func Load(r io.Reader, passphrase []byte) (*rsa.PrivateKey, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("keys: %w", err)
+	}
+	return Parse(data, passphrase)
+}
+
+func parsePEM(data, passphrase []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("keys: invalid PEM data")
+	}
+	switch block.Type {
+	case "PRIVATE KEY":
+		return parsePKCS8(block.Bytes)
+	case "RSA PRIVATE KEY":
+		der := block.Bytes
+		if x509.IsEncryptedPEMBlock(block) { //nolint:staticcheck // legacy encrypted PEM is still in use
+			if len(passphrase) == 0 {
+				return nil, ErrPassphraseRequired
+			}
+			var err error
+			der, err = x509.DecryptPEMBlock(block, passphrase) //nolint:staticcheck // legacy encrypted PEM is still in use
+			if err != nil {
+				return nil, ErrIncorrectPassphrase
+			}
+		}
+		return parsePKCS1(der)
+	case "ENCRYPTED PRIVATE KEY":
+		return parseEncryptedPKCS8(block.Bytes, passphrase)
+	case "PUBLIC KEY", "RSA PUBLIC KEY", "CERTIFICATE":
+		return nil, fmt.Errorf("keys: got %s, want a private key", block.Type)
+	default:
+		return nil, fmt.Errorf("keys: unsupported PEM block type %q", block.Type)
+	}
+}
+
+func parseDER(der, passphrase []byte) (*rsa.PrivateKey, error) {
+	if key, err := parsePKCS8(der); err == nil || !errors.Is(err, ErrUnknownFormat) {
+		return key, err
+	}
+	if key, err := parsePKCS1(der); err == nil {
+		return key, nil
+	}
+	if isEncryptedPKCS8(der) {
+		return parseEncryptedPKCS8(der, passphrase)
+	}
+	return nil, ErrUnknownFormat
+}
+
+func parsePKCS8(der []byte) (*rsa.PrivateKey, error) {
+	keyIF, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnknownFormat, err)
+	}
+	key, ok := keyIF.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("keys: got %T, want an RSA private key", keyIF)
+	}
+	return validate(key)
+}
+
+func parsePKCS1(der []byte) (*rsa.PrivateKey, error) {
+	key, err := x509.ParsePKCS1PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("keys: invalid PKCS#1 private key: %w", err)
+	}
+	return validate(key)
+}
+
+func validate(key *rsa.PrivateKey) (*rsa.PrivateKey, error) {
+	if bits := key.N.BitLen(); bits < MinKeyBits {
+		return nil, fmt.Errorf("keys: RSA key is %d bits, want at least %d", bits, MinKeyBits)
+	}
+	if err := key.Validate(); err != nil {
+		return nil, fmt.Errorf("keys: invalid RSA key: %w", err)
+	}
+	return key, nil
+}
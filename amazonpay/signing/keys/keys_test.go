@@ -0,0 +1,123 @@
+package keys_test
+
+import (
+	"crypto/rsa"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/keys"
+)
+
+// The files in testdata hold the same key, written by OpenSSL 3 with:
+//
+//	openssl genrsa -traditional -out pkcs1.pem 2048
+//	openssl pkcs8 -topk8 -nocrypt -in pkcs1.pem [-outform DER]
+//	openssl rsa -in pkcs1.pem -traditional -outform DER -out pkcs1.der
+//	openssl pkcs8 -topk8 -v2 <cipher> [-v2prf <prf>] [-iter <n>] -passout pass:test-passphrase -in pkcs1.pem
+//	openssl pkcs8 -topk8 -v1 PBE-SHA1-3DES -passout pass:test-passphrase -in pkcs1.pem
+//	openssl rsa -in pkcs1.pem -traditional -<cipher> -passout pass:test-passphrase
+//
+// The .b64 files are the base64 encoding of their base name.
+const passphrase = "test-passphrase"
+
+func load(t *testing.T, name string, passphrase []byte) (*rsa.PrivateKey, error) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys.Parse(data, passphrase)
+}
+
+func TestParse(t *testing.T) {
+	want, err := load(t, "pkcs1.pem", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		file      string
+		encrypted bool
+	}{
+		{"pkcs1.pem", false},
+		{"pkcs1.der", false},
+		{"pkcs1.pem.b64", false},
+		{"pkcs8.pem", false},
+		{"pkcs8.der", false},
+		{"pkcs8.der.b64", false},
+		{"pkcs1_aes256.pem", true},
+		{"pkcs1_des3.pem", true},
+		{"pkcs8_aes256_sha256.pem", true},
+		{"pkcs8_aes256_sha256.der", true},
+		{"pkcs8_aes128_sha1.pem", true},
+		{"pkcs8_aes192_sha512.pem", true},
+		{"pkcs8_des3.pem", true},
+	} {
+		t.Run(tt.file, func(t *testing.T) {
+			key, err := load(t, tt.file, []byte(passphrase))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !key.Equal(want) {
+				t.Error("parsed a different key")
+			}
+			if !tt.encrypted {
+				return
+			}
+			if _, err := load(t, tt.file, nil); !errors.Is(err, keys.ErrPassphraseRequired) {
+				t.Errorf("without passphrase: %v, want ErrPassphraseRequired", err)
+			}
+			if _, err := load(t, tt.file, []byte("wrong")); !errors.Is(err, keys.ErrIncorrectPassphrase) {
+				t.Errorf("with a wrong passphrase: %v, want ErrIncorrectPassphrase", err)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, tt := range []struct {
+		file    string
+		wantErr string
+	}{
+		{"pkcs8_iter_too_high.pem", "iteration count 20000000 out of range"},
+		{"pkcs8_pbes1.pem", "only PBES2 is supported"},
+		{"public.pem", "want a private key"},
+		{"small.pem", "want at least 2048"},
+	} {
+		t.Run(tt.file, func(t *testing.T) {
+			_, err := load(t, tt.file, []byte(passphrase))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadFileAndEnv(t *testing.T) {
+	want, err := load(t, "pkcs8.pem", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := keys.LoadFile(filepath.Join("testdata", "pkcs8_aes256_sha256.pem"), []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.Equal(want) {
+		t.Error("LoadFile parsed a different key")
+	}
+
+	b64, err := os.ReadFile(filepath.Join("testdata", "pkcs8.der.b64"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AMAZON_PAY_TEST_PRIVATE_KEY", string(b64))
+	key, err = keys.LoadEnv("AMAZON_PAY_TEST_PRIVATE_KEY", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.Equal(want) {
+		t.Error("LoadEnv parsed a different key")
+	}
+}
@@ -0,0 +1,149 @@
+package remote
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+)
+
+const maxRequestBodySize = 64 << 10
+
+// AuditEntry is written to Handler.Audit as a JSON line for every sign request.
+type AuditEntry struct {
+	Time         time.Time `json:"time"`
+	RemoteAddr   string    `json:"remoteAddr"`
+	StringToSign string    `json:"stringToSign"`
+	Signed       bool      `json:"signed"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// Handler serves the signing daemon API.
+type Handler struct {
+	Signer crypto.Signer
+	// Token is required as a bearer token on every request.
+	Token string
+	// AllowedPrefixes lists the algorithm labels accepted as the first line of a string to sign.
+	// It defaults to the names of signing.AlgorithmV1 and signing.AlgorithmV2.
+	AllowedPrefixes []string
+	// Audit receives an AuditEntry for every sign request when set.
+	Audit io.Writer
+
+	mu sync.Mutex
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, &signResponse{Error: "unauthorized"})
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == PublicKeyPath:
+		h.servePublicKey(w)
+	case r.Method == http.MethodPost && r.URL.Path == SignPath:
+		h.serveSign(w, r)
+	default:
+		writeJSON(w, http.StatusNotFound, &signResponse{Error: "not found"})
+	}
+}
+
+func (h *Handler) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && h.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.Token)) == 1
+}
+
+func (h *Handler) servePublicKey(w http.ResponseWriter) {
+	der, err := x509.MarshalPKIXPublicKey(h.Signer.Public())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, &signResponse{Error: err.Error()})
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-pem-file")
+	_ = pem.Encode(w, &pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func (h *Handler) serveSign(w http.ResponseWriter, r *http.Request) {
+	var req signRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestBodySize)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, &signResponse{Error: "invalid request body"})
+		return
+	}
+	if err := h.checkStringToSign(req.StringToSign); err != nil {
+		h.audit(r, req.StringToSign, err)
+		writeJSON(w, http.StatusForbidden, &signResponse{Error: err.Error()})
+		return
+	}
+	alg, _ := signing.AlgorithmByName(label(req.StringToSign))
+	signature, err := signing.Sign(h.Signer, alg, req.StringToSign)
+	h.audit(r, req.StringToSign, err)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, &signResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, &signResponse{Signature: signature})
+}
+
+// checkStringToSign accepts only "<allowed prefix>\n<hex encoded sha256>".
+func (h *Handler) checkStringToSign(stringToSign string) error {
+	prefixes := h.AllowedPrefixes
+	if len(prefixes) == 0 {
+		prefixes = []string{signing.AlgorithmV1.Name(), signing.AlgorithmV2.Name()}
+	}
+	name, hash, ok := strings.Cut(stringToSign, "\n")
+	if !ok {
+		return errors.New("malformed string to sign")
+	}
+	allowed := false
+	for _, prefix := range prefixes {
+		if name == prefix {
+			allowed = true
+			break
+		}
+	}
+	if _, ok := signing.AlgorithmByName(name); !allowed || !ok {
+		return errors.New("string to sign prefix not allowed")
+	}
+	if b, err := hex.DecodeString(hash); err != nil || len(b) != 32 {
+		return errors.New("malformed string to sign hash")
+	}
+	return nil
+}
+
+func label(stringToSign string) string {
+	name, _, _ := strings.Cut(stringToSign, "\n")
+	return name
+}
+
+func (h *Handler) audit(r *http.Request, stringToSign string, err error) {
+	if h.Audit == nil {
+		return
+	}
+	entry := &AuditEntry{
+		Time:         time.Now().UTC(),
+		RemoteAddr:   r.RemoteAddr,
+		StringToSign: stringToSign,
+		Signed:       err == nil,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_ = json.NewEncoder(h.Audit).Encode(entry)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,119 @@
+package remote_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/remote"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+const hash = "c2a5e5d0b7e5f1dca3ae1f8b24b2d0f2a4a9b48e1fbd1c0f6e3b9bd8e9fd6c11"
+
+func serve(h http.Handler, method, path, authorization, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func signBody(stringToSign string) string {
+	b, _ := json.Marshal(map[string]string{"stringToSign": stringToSign})
+	return string(b)
+}
+
+func TestHandlerRequiresToken(t *testing.T) {
+	h := &remote.Handler{Signer: signingtest.Signer(), Token: token}
+	for _, authorization := range []string{"", "Bearer wrong", "Bearer " + token + "x", "Basic " + token, token, "Bearer "} {
+		for _, path := range []string{remote.PublicKeyPath, remote.SignPath} {
+			method := http.MethodGet
+			if path == remote.SignPath {
+				method = http.MethodPost
+			}
+			rec := serve(h, method, path, authorization, signBody(signing.AlgorithmV1.Name()+"\n"+hash))
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("%s with Authorization %q: status %d, want 401", path, authorization, rec.Code)
+			}
+		}
+	}
+	if rec := serve(h, http.MethodGet, remote.PublicKeyPath, "Bearer "+token, ""); rec.Code != http.StatusOK {
+		t.Errorf("public key with the token: status %d", rec.Code)
+	}
+
+	// A handler without a token accepts nobody, not an empty bearer token.
+	open := &remote.Handler{Signer: signingtest.Signer()}
+	if rec := serve(open, http.MethodGet, remote.PublicKeyPath, "Bearer ", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("handler without token: status %d, want 401", rec.Code)
+	}
+}
+
+func TestHandlerPrefixAllowlist(t *testing.T) {
+	v1, v2 := signing.AlgorithmV1.Name(), signing.AlgorithmV2.Name()
+	for _, tt := range []struct {
+		name         string
+		allowed      []string
+		stringToSign string
+		want         int
+	}{
+		{"default v1", nil, v1 + "\n" + hash, http.StatusOK},
+		{"default v2", nil, v2 + "\n" + hash, http.StatusOK},
+		{"v1 only", []string{v1}, v2 + "\n" + hash, http.StatusForbidden},
+		{"v1 only v1", []string{v1}, v1 + "\n" + hash, http.StatusOK},
+		{"unknown algorithm", []string{"AWS4-HMAC-SHA256"}, "AWS4-HMAC-SHA256\n" + hash, http.StatusForbidden},
+		{"no prefix", nil, hash, http.StatusForbidden},
+		{"short hash", nil, v1 + "\n" + hash[:62], http.StatusForbidden},
+		{"not hex", nil, v1 + "\n" + strings.Repeat("zz", 32), http.StatusForbidden},
+		{"trailing line", nil, v1 + "\n" + hash + "\n", http.StatusForbidden},
+		{"arbitrary payload", nil, `{"amount":"1"}`, http.StatusForbidden},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &remote.Handler{Signer: signingtest.Signer(), Token: token, AllowedPrefixes: tt.allowed}
+			rec := serve(h, http.MethodPost, remote.SignPath, "Bearer "+token, signBody(tt.stringToSign))
+			if rec.Code != tt.want {
+				t.Errorf("status %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+	h := &remote.Handler{Signer: signingtest.Signer(), Token: token}
+	if rec := serve(h, http.MethodPost, remote.SignPath, "Bearer "+token, "{"); rec.Code != http.StatusBadRequest {
+		t.Errorf("malformed body: status %d, want 400", rec.Code)
+	}
+}
+
+func TestHandlerAudit(t *testing.T) {
+	var audit bytes.Buffer
+	h := &remote.Handler{Signer: signingtest.Signer(), Token: token, Audit: &audit}
+	signed := signing.AlgorithmV1.Name() + "\n" + hash
+	serve(h, http.MethodPost, remote.SignPath, "Bearer "+token, signBody(signed))
+	serve(h, http.MethodPost, remote.SignPath, "Bearer "+token, signBody("rejected"))
+	serve(h, http.MethodPost, remote.SignPath, "Bearer wrong", signBody(signed))
+	serve(h, http.MethodGet, remote.PublicKeyPath, "Bearer "+token, "")
+
+	var entries []remote.AuditEntry
+	scanner := bufio.NewScanner(&audit)
+	for scanner.Scan() {
+		var entry remote.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("audit line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("%d audit lines, want one per sign request past authorization:\n%s", len(entries), audit.String())
+	}
+	if e := entries[0]; !e.Signed || e.StringToSign != signed || e.Error != "" || e.RemoteAddr == "" || e.Time.IsZero() {
+		t.Errorf("signed entry %+v", e)
+	}
+	if e := entries[1]; e.Signed || e.StringToSign != "rejected" || e.Error == "" {
+		t.Errorf("rejected entry %+v", e)
+	}
+}
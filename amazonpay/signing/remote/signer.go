@@ -0,0 +1,173 @@
+// Package remote keeps the private key in a separate signing process.
+// Handler is served by the daemon holding the key, and Signer is used by the Client to reach it.
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	SignPath      = "/sign"
+	PublicKeyPath = "/public-key"
+)
+
+// ErrDigestSigning is returned by Signer.Sign, as the daemon only signs strings to sign.
+var ErrDigestSigning = errors.New("remote: digest signing is not supported, use SignString")
+
+type signRequest struct {
+	StringToSign string `json:"stringToSign"`
+}
+
+type signResponse struct {
+	Signature string `json:"signature,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Signer is a crypto.Signer backed by a signing daemon.
+// It implements signing.StringSigner so that signing.Sign sends the string to sign to the daemon.
+type Signer struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+	publicKey  *rsa.PublicKey
+}
+
+// NewSigner connects to the daemon at addr and fetches its public key.
+// addr is either "unix:/path/to/socket" or a local http URL such as "http://127.0.0.1:8900",
+// http URLs of other hosts are rejected so that the token is never sent over the network.
+func NewSigner(ctx context.Context, addr, token string) (*Signer, error) {
+	if token == "" {
+		return nil, errors.New("missing token")
+	}
+	if !strings.HasPrefix(addr, "unix:") {
+		u, err := url.Parse(addr)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme != "http" || !IsLoopbackHost(u.Hostname()) {
+			return nil, fmt.Errorf("remote: %s is neither a unix socket nor a loopback http URL", addr)
+		}
+	}
+	s := &Signer{
+		baseURL:    strings.TrimSuffix(addr, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+	if path, ok := strings.CutPrefix(addr, "unix:"); ok {
+		s.baseURL = "http://unix"
+		s.httpClient.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		}
+	}
+	publicKey, err := s.fetchPublicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.publicKey = publicKey
+	return s, nil
+}
+
+// IsLoopbackHost reports whether host is "localhost" or a loopback IP address.
+func IsLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (s *Signer) do(ctx context.Context, method, path string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var signResp signResponse
+		if json.Unmarshal(b, &signResp) == nil && signResp.Error != "" {
+			return nil, fmt.Errorf("remote: %s: %s", resp.Status, signResp.Error)
+		}
+		return nil, fmt.Errorf("remote: %s", resp.Status)
+	}
+	return b, nil
+}
+
+func (s *Signer) fetchPublicKey(ctx context.Context) (*rsa.PublicKey, error) {
+	b, err := s.do(ctx, http.MethodGet, PublicKeyPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("remote: invalid public key data")
+	}
+	keyIF, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := keyIF.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("remote: not RSA public key")
+	}
+	return key, nil
+}
+
+// Public returns the public key of the daemon.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign always returns ErrDigestSigning.
+func (s *Signer) Sign(_ io.Reader, _ []byte, _ crypto.SignerOpts) ([]byte, error) {
+	return nil, ErrDigestSigning
+}
+
+// SignString asks the daemon to sign stringToSign and returns the base64 encoded signature.
+func (s *Signer) SignString(stringToSign string) (string, error) {
+	return s.SignStringContext(context.Background(), stringToSign)
+}
+
+// SignStringContext is SignString canceling the call to the daemon when ctx is done.
+// signing.Transport passes the context of the request being signed.
+func (s *Signer) SignStringContext(ctx context.Context, stringToSign string) (string, error) {
+	body, err := json.Marshal(&signRequest{StringToSign: stringToSign})
+	if err != nil {
+		return "", err
+	}
+	b, err := s.do(ctx, http.MethodPost, SignPath, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	var resp signResponse
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return "", err
+	}
+	return resp.Signature, nil
+}
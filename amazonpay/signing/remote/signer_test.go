@@ -0,0 +1,110 @@
+package remote_test
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/remote"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+const token = "test-token"
+
+func newDaemon(t *testing.T, handler http.Handler) *httptest.Server {
+	t.Helper()
+	if handler == nil {
+		handler = &remote.Handler{Signer: signingtest.Signer(), Token: token}
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSignerSignsVerifiableRequests(t *testing.T) {
+	server := newDaemon(t, nil)
+	signer, err := remote.NewSigner(context.Background(), server.URL, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := &signing.Transport{PublicKeyID: signingtest.PublicKeyID, Signer: signer, Region: "jp"}
+	req, err := http.NewRequest(http.MethodGet, "https://pay-api.amazon.jp/v2/charges/S03-1", nil) //nolint:noctx // test
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := transport.Sign(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = signing.VerifyRequest(signed, func(string) (*rsa.PublicKey, error) {
+		return signer.Public().(*rsa.PublicKey), nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSignerHonorsRequestContext(t *testing.T) {
+	daemon := &remote.Handler{Signer: signingtest.Signer(), Token: token}
+	block := make(chan struct{})
+	server := newDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == remote.SignPath {
+			select {
+			case <-block:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		daemon.ServeHTTP(w, r)
+	}))
+	defer close(block)
+	signer, err := remote.NewSigner(context.Background(), server.URL, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://pay-api.amazon.jp/v2/charges/S03-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := &signing.Transport{PublicKeyID: signingtest.PublicKeyID, Signer: signer, Region: "jp"}
+	if _, err := transport.Sign(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("Sign = %v, want context.Canceled", err)
+	}
+}
+
+func TestNewSignerRejectsNetworkAddresses(t *testing.T) {
+	for _, addr := range []string{
+		"http://10.0.0.1:8900",
+		"http://signer.example.com:8900",
+		"https://127.0.0.1:8900",
+		"127.0.0.1:8900",
+	} {
+		if _, err := remote.NewSigner(context.Background(), addr, token); err == nil {
+			t.Errorf("NewSigner(%q) succeeded, want an error", addr)
+		}
+	}
+}
+
+func TestIsLoopbackHost(t *testing.T) {
+	for host, want := range map[string]bool{
+		"localhost": true,
+		"127.0.0.1": true,
+		"127.1.2.3": true,
+		"::1":       true,
+		"0.0.0.0":   false,
+		"":          false,
+		"10.0.0.1":  false,
+		"example":   false,
+	} {
+		if got := remote.IsLoopbackHost(host); got != want {
+			t.Errorf("IsLoopbackHost(%q) = %t, want %t", host, got, want)
+		}
+	}
+}
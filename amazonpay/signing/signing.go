@@ -2,26 +2,35 @@ package signing
 
 import (
 	"bytes"
+	"context"
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
-	"crypto/x509"
 	"encoding/base64"
-	"encoding/pem"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"reflect"
 	"sort"
 	"strings"
+	"sync"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/keys"
 )
 
 const (
+	// Algorithm is the name of AlgorithmV1.
 	Algorithm = "AMZN-PAY-RSASSA-PSS"
+	// AlgorithmNameV2 is the name of AlgorithmV2.
+	AlgorithmNameV2 = "AMZN-PAY-RSASSA-PSS-V2"
 )
 
+const hashFunc = crypto.SHA256
+
 // CanonicalRequest =
 //
 //	HTTPRequestMethod + '\n' +
@@ -30,79 +39,192 @@ const (
 //	CanonicalHeaders + '\n' +
 //	SignedHeaders + '\n' +
 //	HexEncode(Hash(RequestPayload))
+//
+// It does not modify r, except for buffering a body that has no GetBody (see PayloadHash).
 func CanonicalRequest(r *http.Request) (string, error) {
-	data, err := RequestPayload(r)
+	return CanonicalRequestWithHeaders(r, SignedHeaderNames(r, SignAllHeaders))
+}
+
+// CanonicalRequestWithHeaders is CanonicalRequest signing only the headers in names,
+// as returned by SignedHeaderNames.
+func CanonicalRequestWithHeaders(r *http.Request, names []string) (string, error) {
+	hexencode, err := PayloadHash(r)
 	if err != nil {
 		return "", err
 	}
-	hexencode, err := HexEncodeSHA256Hash(data)
-	return fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s", r.Method, CanonicalURI(r), CanonicalQueryString(r), CanonicalHeaders(r), SignedHeaders(r), hexencode), err
+	buf := getBuffer()
+	defer putBuffer(buf)
+	buf.WriteString(r.Method)
+	buf.WriteByte('\n')
+	writeCanonicalURI(buf, r.URL.Path)
+	buf.WriteByte('\n')
+	writeCanonicalQueryString(buf, r.URL.Query())
+	buf.WriteByte('\n')
+	writeCanonicalHeaders(buf, r.Header, names)
+	buf.WriteByte('\n')
+	writeSignedHeaders(buf, names)
+	buf.WriteByte('\n')
+	buf.WriteString(hexencode)
+	return buf.String(), nil
+}
+
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+func getBuffer() *bytes.Buffer {
+	buf, _ := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
 }
 
-// CanonicalURI returns request uri.
+func putBuffer(buf *bytes.Buffer) {
+	// Do not keep the buffers of unusually large requests alive.
+	if buf.Cap() > 64<<10 {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// CanonicalURI returns the normalized and escaped request path without modifying r.
 func CanonicalURI(r *http.Request) string {
-	pattens := strings.Split(r.URL.Path, "/")
-	var uri []string
-	for _, v := range pattens {
-		switch v {
-		case "":
-			continue
-		case ".":
-			continue
+	buf := getBuffer()
+	defer putBuffer(buf)
+	writeCanonicalURI(buf, r.URL.Path)
+	return buf.String()
+}
+
+func writeCanonicalURI(buf *bytes.Buffer, path string) {
+	var stack [16]string
+	segments := stack[:0]
+	for len(path) > 0 {
+		var segment string
+		if i := strings.IndexByte(path, '/'); i >= 0 {
+			segment, path = path[:i], path[i+1:]
+		} else {
+			segment, path = path, ""
+		}
+		switch segment {
+		case "", ".":
 		case "..":
-			if len(uri) > 0 {
-				uri = uri[:len(uri)-1]
+			if len(segments) > 0 {
+				segments = segments[:len(segments)-1]
 			}
 		default:
-			uri = append(uri, url.QueryEscape(v))
+			segments = append(segments, segment)
 		}
 	}
-	urlpath := "/" + strings.Join(uri, "/")
-	r.URL.Path = strings.ReplaceAll(urlpath, "+", "%20")
-	return r.URL.Path
+	buf.WriteByte('/')
+	for i, segment := range segments {
+		if i > 0 {
+			buf.WriteByte('/')
+		}
+		writeEscaped(buf, segment)
+	}
+}
+
+// writeEscaped writes url.QueryEscape(s) with spaces escaped as %20 instead of '+'.
+func writeEscaped(buf *bytes.Buffer, s string) {
+	escaped := url.QueryEscape(s)
+	for {
+		i := strings.IndexByte(escaped, '+')
+		if i < 0 {
+			buf.WriteString(escaped)
+			return
+		}
+		buf.WriteString(escaped[:i])
+		buf.WriteString("%20")
+		escaped = escaped[i+1:]
+	}
 }
 
 func CanonicalQueryString(r *http.Request) string {
-	var a []string
-	for key, value := range r.URL.Query() {
-		k := url.QueryEscape(key)
-		for _, v := range value {
-			var kv string
+	buf := getBuffer()
+	defer putBuffer(buf)
+	writeCanonicalQueryString(buf, r.URL.Query())
+	return buf.String()
+}
+
+func writeCanonicalQueryString(buf *bytes.Buffer, query url.Values) {
+	if len(query) == 0 {
+		return
+	}
+	n := 0
+	for _, values := range query {
+		n += len(values)
+	}
+	pairs := make([]string, 0, n)
+	for key, values := range query {
+		k := strings.ReplaceAll(url.QueryEscape(key), "+", "%20")
+		for _, v := range values {
 			if v == "" {
-				kv = k
-			} else {
-				kv = fmt.Sprintf("%s=%s", k, url.QueryEscape(v))
+				pairs = append(pairs, k)
+				continue
 			}
-			a = append(a, strings.ReplaceAll(kv, "+", "%20"))
+			pairs = append(pairs, k+"="+strings.ReplaceAll(url.QueryEscape(v), "+", "%20"))
+		}
+	}
+	sort.Strings(pairs)
+	for i, pair := range pairs {
+		if i > 0 {
+			buf.WriteByte('&')
 		}
+		buf.WriteString(pair)
 	}
-	sort.Strings(a)
-	return strings.Join(a, "&")
 }
 
 func CanonicalHeaders(r *http.Request) string {
-	var a []string
-	for key, value := range r.Header {
-		sort.Strings(value)
-		var q []string
-		for _, v := range value {
-			q = append(q, trimString(v))
+	return canonicalHeaders(r.Header, SignedHeaderNames(r, SignAllHeaders))
+}
+
+func SignedHeaders(r *http.Request) string {
+	return strings.Join(SignedHeaderNames(r, SignAllHeaders), ";")
+}
+
+func writeSignedHeaders(buf *bytes.Buffer, names []string) {
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteByte(';')
 		}
-		a = append(a, strings.ToLower(key)+":"+strings.Join(q, ","))
+		buf.WriteString(name)
 	}
-	sort.Strings(a)
-	return fmt.Sprintf("%s\n", strings.Join(a, "\n"))
 }
 
-func SignedHeaders(r *http.Request) string {
-	var a []string
-	for key := range r.Header {
-		a = append(a, strings.ToLower(key))
+// PayloadHash returns the hex encoded SHA-256 hash of the request body.
+// The body is streamed from GetBody when set, so r is left untouched.
+// Otherwise the body is read into memory once and r.Body and r.GetBody are replaced to replay it.
+func PayloadHash(r *http.Request) (string, error) {
+	hash := sha256.New()
+	var sum [sha256.Size]byte
+	switch {
+	case r.Body == nil || r.Body == http.NoBody:
+	case r.GetBody != nil:
+		body, err := r.GetBody()
+		if err != nil {
+			return "", err
+		}
+		defer body.Close()
+		if _, err := io.Copy(hash, body); err != nil {
+			return "", err
+		}
+	default:
+		b, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(b))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(b)), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		hash.Write(b)
 	}
-	sort.Strings(a)
-	return strings.Join(a, ";")
+	return hex.EncodeToString(hash.Sum(sum[:0])), nil
 }
 
+// RequestPayload reads the whole body of r into memory and replaces r.Body to replay it.
+//
+// Deprecated: Use PayloadHash, which streams bodies that have GetBody set.
 func RequestPayload(r *http.Request) ([]byte, error) {
 	if r.Body == nil {
 		return []byte(""), nil
@@ -112,31 +234,91 @@ func RequestPayload(r *http.Request) ([]byte, error) {
 	return b, err
 }
 
-func StringToSign(canonicalRequest string) (string, error) {
-	hexencode, err := HexEncodeSHA256Hash([]byte(canonicalRequest))
+func StringToSign(alg SignatureAlgorithm, canonicalRequest string) (string, error) {
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	return alg.Name() + "\n" + hex.EncodeToString(hashed[:]), nil
+}
+
+// ParsePrivateKey parses an unencrypted RSA private key in any format accepted by keys.Parse.
+func ParsePrivateKey(privateKeyData []byte) (crypto.Signer, error) {
+	key, err := keys.Parse(privateKeyData, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return fmt.Sprintf("%s\n%s", Algorithm, hexencode), nil
+	return key, nil
 }
 
-func Sign(privateKeyData []byte, stringToSign string) (string, error) {
-	block, _ := pem.Decode(privateKeyData)
-	if block == nil {
-		return "", errors.New("invalid private key data")
+// ValidateSigner checks that signer holds an RSA key.
+// A nil pointer in a non-nil interface, such as the key of an ignored keys.LoadFile error, is rejected as missing.
+func ValidateSigner(signer crypto.Signer) (err error) {
+	if isNil(signer) {
+		return errors.New("missing signer")
 	}
-	keyIF, err := x509.ParsePKCS8PrivateKey(block.Bytes)
-	if err != nil {
-		return "", err
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid signer: %v", r)
+		}
+	}()
+	if pub, ok := signer.Public().(*rsa.PublicKey); !ok || pub == nil || pub.N == nil {
+		return errors.New("not RSA signer")
+	}
+	return nil
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+		return rv.IsNil()
+	default:
+		return false
 	}
-	key, ok := keyIF.(*rsa.PrivateKey)
-	if !ok {
-		return "", errors.New("not RSA private key")
+}
+
+// StringSigner is implemented by signers that need the string to sign rather than its digest,
+// such as signers delegating to another process.
+type StringSigner interface {
+	SignString(stringToSign string) (string, error)
+}
+
+// ContextStringSigner is a StringSigner that honors the cancellation and deadline of ctx,
+// the context of the request being signed.
+type ContextStringSigner interface {
+	StringSigner
+	SignStringContext(ctx context.Context, stringToSign string) (string, error)
+}
+
+// Sign signs stringToSign with the RSASSA-PSS parameters of alg through signer.
+// When signer implements StringSigner, SignString is used instead.
+func Sign(signer crypto.Signer, alg SignatureAlgorithm, stringToSign string) (string, error) {
+	return sign(context.Background(), rand.Reader, signer, alg, stringToSign)
+}
+
+// SignContext is Sign passing ctx to signers implementing ContextStringSigner.
+func SignContext(ctx context.Context, signer crypto.Signer, alg SignatureAlgorithm, stringToSign string) (string, error) {
+	return sign(ctx, rand.Reader, signer, alg, stringToSign)
+}
+
+// SignWithRand is Sign reading the PSS salt from random.
+// It is meant for reproducible test vectors only, production code must use Sign.
+// crypto/rsa reads the PSS salt from random with every supported Go version,
+// GODEBUG=cryptocustomrand only concerns key generation and encryption.
+// TestVectors of package signingtest fails if that ever changes.
+func SignWithRand(random io.Reader, signer crypto.Signer, alg SignatureAlgorithm, stringToSign string) (string, error) {
+	return sign(context.Background(), random, signer, alg, stringToSign)
+}
+
+func sign(ctx context.Context, random io.Reader, signer crypto.Signer, alg SignatureAlgorithm, stringToSign string) (string, error) {
+	switch s := signer.(type) {
+	case ContextStringSigner:
+		return s.SignStringContext(ctx, stringToSign)
+	case StringSigner:
+		return s.SignString(stringToSign)
 	}
 	hashed := sha256.Sum256([]byte(stringToSign))
-	signature, err := rsa.SignPSS(rand.Reader, key, crypto.SHA256, hashed[:], &rsa.PSSOptions{
-		SaltLength: 20,
-	})
+	signature, err := signer.Sign(random, hashed[:], pssOptions(alg))
 	if err != nil {
 		return "", err
 	}
@@ -144,23 +326,22 @@ func Sign(privateKeyData []byte, stringToSign string) (string, error) {
 }
 
 func HexEncodeSHA256Hash(body []byte) (string, error) {
-	hash := sha256.New()
-	if body == nil {
-		body = []byte("")
-	}
-	_, err := hash.Write(body)
-	return fmt.Sprintf("%x", hash.Sum(nil)), err
+	hashed := sha256.Sum256(body)
+	return hex.EncodeToString(hashed[:]), nil
 }
 
-func AuthHeaderValue(publicKeyID, signedHeaders, signature string) string {
-	return fmt.Sprintf("%s PublicKeyId=%s, SignedHeaders=%s, Signature=%s", Algorithm, publicKeyID, signedHeaders, signature)
+func AuthHeaderValue(alg SignatureAlgorithm, publicKeyID, signedHeaders, signature string) string {
+	return alg.Name() + " PublicKeyId=" + publicKeyID + ", SignedHeaders=" + signedHeaders + ", Signature=" + signature
 }
 
 func trimString(s string) string {
-	var trimedString []byte
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, "  ") {
+		return s
+	}
+	trimedString := make([]byte, 0, len(s))
 	inQuote := false
 	var lastChar byte
-	s = strings.TrimSpace(s)
 	for _, v := range []byte(s) {
 		if v == '"' {
 			inQuote = !inQuote
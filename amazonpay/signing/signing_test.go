@@ -0,0 +1,30 @@
+package signing_test
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+func TestValidateSigner(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		signer  crypto.Signer
+		wantErr bool
+	}{
+		{"rsa", signingtest.Signer(), false},
+		{"nil", nil, true},
+		{"typed nil", (*rsa.PrivateKey)(nil), true},
+		{"zero key", &rsa.PrivateKey{}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := signing.ValidateSigner(tt.signer)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSigner = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
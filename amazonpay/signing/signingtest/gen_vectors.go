@@ -0,0 +1,72 @@
+//go:build ignore
+
+// gen_vectors regenerates testdata/vectors.json.
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+const base = "https://pay-api.amazon.jp/sandbox"
+
+func header(extra ...string) http.Header {
+	h := http.Header{
+		"Accept":           {"application/json"},
+		"Content-Type":     {"application/json"},
+		"User-Agent":       {"amazon-pay-api-sdk-go/2.2.1 (GO/go1.22.4)"},
+		"X-Amz-Pay-Date":   {"20240101T000000Z"},
+		"X-Amz-Pay-Host":   {"pay-api.amazon.jp"},
+		"X-Amz-Pay-Region": {"jp"},
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		h.Add(extra[i], extra[i+1])
+	}
+	return h
+}
+
+func main() {
+	idempotency := []string{"X-Amz-Pay-Idempotency-Key", "cpmvqbd2cnmhbqt6k3ag"}
+	nonCanonical := header("X-Custom", "a")
+	nonCanonical["x-custom"] = []string{"b"}
+	inputs := []signingtest.Vector{
+		{Name: "get-charge", Method: http.MethodGet, URL: base + "/v2/charges/S03-1234567-1234567-C123456", Header: header()},
+		{Name: "get-charge-v2", Algorithm: signing.AlgorithmV2.Name(), Method: http.MethodGet, URL: base + "/v2/charges/S03-1234567-1234567-C123456", Header: header()},
+		{Name: "post-charge", Method: http.MethodPost, URL: base + "/v2/charges", Header: header(idempotency...), Body: `{"chargePermissionId":"B03-1234567-1234567","chargeAmount":{"amount":"1000","currencyCode":"JPY"},"captureNow":true}`},
+		{Name: "post-charge-v2", Algorithm: signing.AlgorithmV2.Name(), Method: http.MethodPost, URL: base + "/v2/charges", Header: header(idempotency...), Body: `{"chargePermissionId":"B03-1234567-1234567","chargeAmount":{"amount":"1000","currencyCode":"JPY"},"captureNow":true}`},
+		{Name: "patch-checkout-session", Method: http.MethodPatch, URL: base + "/v2/checkoutSessions/bd504926-f659-4ad7-a1a9-9a747aaf5275", Header: header(), Body: `{"webCheckoutDetails":{"checkoutResultReturnUrl":"https://example.com/result?a=1&b=2"}}`},
+		{Name: "delete-close-charge-permission", Method: http.MethodDelete, URL: base + "/v2/chargePermissions/B03-1234567-1234567/close", Header: header(), Body: `{"closureReason":"No more charges","cancelPendingCharges":false}`},
+		{Name: "get-query-string", Method: http.MethodGet, URL: base + "/v2/reports?reportTypes=_GET_FLAT_FILE_OFFAMAZONPAYMENTS_ORDER_REFERENCE_DATA_&processingStatus=COMPLETED&b=2&a=1&a=0", Header: header()},
+		{Name: "get-query-escaping", Method: http.MethodGet, URL: base + "/v2/reports?q=a+b&space=a%20b&empty=&tilde=~&slash=a%2Fb&unicode=%E3%81%82", Header: header()},
+		{Name: "get-dot-segments", Method: http.MethodGet, URL: base + "/v2/./charges/../charges/S03-1", Header: header()},
+		{Name: "get-double-slash-trailing-slash", Method: http.MethodGet, URL: base + "//v2//charges/S03-1/", Header: header()},
+		{Name: "get-encoded-path", Method: http.MethodGet, URL: base + "/v2/charges/a%20b+c%2Bd", Header: header()},
+		{Name: "get-header-whitespace", Method: http.MethodGet, URL: base + "/v2/charges/S03-1", Header: header("X-Custom", `  a   b  "c   d"  `, "X-Multi", "z", "X-Multi", "y")},
+		{Name: "get-dot-segments-above-root", Method: http.MethodGet, URL: base + "/../../v2/./charges/S03-1/x/..", Header: header()},
+		{Name: "get-query-repeated-keys", Method: http.MethodGet, URL: base + "/v2/reports?a=%2B&a=+&a=b%20c&a&A=1", Header: header()},
+		{Name: "get-header-unbalanced-quote", Method: http.MethodGet, URL: base + "/v2/charges/S03-1", Header: header("X-Custom", `"a   b" c   "d   e`, "X-Empty", "")},
+		{Name: "get-header-non-canonical-key", Method: http.MethodGet, URL: base + "/v2/charges/S03-1", Header: nonCanonical},
+	}
+	vectors := make([]*signingtest.Vector, 0, len(inputs))
+	for i := range inputs {
+		if inputs[i].Algorithm == "" {
+			inputs[i].Algorithm = signing.AlgorithmV1.Name()
+		}
+		v, err := inputs[i].Compute()
+		if err != nil {
+			panic(err)
+		}
+		vectors = append(vectors, v)
+	}
+	b, err := json.MarshalIndent(vectors, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile("testdata/vectors.json", append(b, '\n'), 0o644); err != nil { //nolint:gosec // test data
+		panic(err)
+	}
+}
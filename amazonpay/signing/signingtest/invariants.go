@@ -0,0 +1,69 @@
+package signingtest
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+)
+
+// CheckInvariants checks the properties every request must satisfy when signed, for use by fuzz targets
+// and with the test vectors as seed corpus:
+//
+//   - canonicalization does not modify the request and returns the same result twice,
+//   - CanonicalURI is idempotent,
+//   - signing.Transport signs the request without panicking and the result passes signing.VerifyRequest.
+func CheckInvariants(req *http.Request) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	if req.URL == nil {
+		return errors.New("missing request URL")
+	}
+
+	rawURL := req.URL.String()
+	header := req.Header.Clone()
+	first, err := signing.CanonicalRequest(req)
+	if err != nil {
+		return err
+	}
+	second, err := signing.CanonicalRequest(req)
+	if err != nil {
+		return err
+	}
+	if first != second {
+		return fmt.Errorf("canonical request is not stable:\n%q\n%q", first, second)
+	}
+	if req.URL.String() != rawURL || !reflect.DeepEqual(req.Header, header) {
+		return errors.New("canonicalization modified the request")
+	}
+
+	uri := signing.CanonicalURI(req)
+	again := req.Clone(req.Context())
+	again.URL.RawPath = uri
+	again.URL.Path, _ = url.PathUnescape(uri)
+	if got := signing.CanonicalURI(again); got != uri {
+		return fmt.Errorf("canonical URI is not idempotent: %q then %q", uri, got)
+	}
+
+	transport := &signing.Transport{
+		PublicKeyID: PublicKeyID,
+		Signer:      Signer(),
+		Region:      "jp",
+		Now:         func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) },
+	}
+	signed, err := transport.Sign(req)
+	if err != nil {
+		return err
+	}
+	return signing.VerifyRequest(signed, func(string) (*rsa.PublicKey, error) {
+		return Signer().Public().(*rsa.PublicKey), nil
+	})
+}
@@ -0,0 +1,59 @@
+// Package signingtest provides a fixed key, a deterministic salt source and a corpus of
+// signing test vectors. It is for tests only and must not be used to sign real requests.
+package signingtest
+
+import (
+	"crypto"
+	"crypto/sha256"
+	_ "embed" // for test data
+	"encoding/binary"
+	"io"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+)
+
+// PublicKeyID is the public key id used by the test vectors.
+const PublicKeyID = "SANDBOX-TESTVECTORKEY0000000000"
+
+// PrivateKeyPEM is the PKCS#8 PEM encoded private key of the test vectors.
+//
+//go:embed testdata/private_key.pem
+var PrivateKeyPEM []byte
+
+// Signer returns the signer of PrivateKeyPEM.
+func Signer() crypto.Signer {
+	signer, err := signing.ParsePrivateKey(PrivateKeyPEM)
+	if err != nil {
+		panic(err)
+	}
+	return signer
+}
+
+type deterministicReader struct {
+	seed    []byte
+	counter uint64
+	buf     []byte
+}
+
+// NewDeterministicReader returns a reader producing the same byte stream for the same seed,
+// SHA-256(seed || big endian counter) for counter = 0, 1, ...
+func NewDeterministicReader(seed string) io.Reader {
+	return &deterministicReader{seed: []byte(seed)}
+}
+
+func (r *deterministicReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			var counter [8]byte
+			binary.BigEndian.PutUint64(counter[:], r.counter)
+			r.counter++
+			block := sha256.Sum256(append(append([]byte{}, r.seed...), counter[:]...))
+			r.buf = block[:]
+		}
+		c := copy(p[n:], r.buf)
+		r.buf = r.buf[c:]
+		n += c
+	}
+	return n, nil
+}
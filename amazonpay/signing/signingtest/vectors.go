@@ -0,0 +1,110 @@
+package signingtest
+
+import (
+	"crypto/rsa"
+	_ "embed" // for test data
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+)
+
+//go:generate go run gen_vectors.go
+
+//go:embed testdata/vectors.json
+var vectorsJSON []byte
+
+// Vector is a single signing test vector.
+// The signature is made with Signer and a NewDeterministicReader seeded with Name.
+type Vector struct {
+	Name             string      `json:"name"`
+	Algorithm        string      `json:"algorithm"`
+	Method           string      `json:"method"`
+	URL              string      `json:"url"`
+	Header           http.Header `json:"header"`
+	Body             string      `json:"body,omitempty"`
+	CanonicalRequest string      `json:"canonicalRequest"`
+	StringToSign     string      `json:"stringToSign"`
+	Signature        string      `json:"signature"`
+	Authorization    string      `json:"authorization"`
+}
+
+// Vectors returns the test vector corpus.
+func Vectors() ([]Vector, error) {
+	var vectors []Vector
+	if err := json.Unmarshal(vectorsJSON, &vectors); err != nil {
+		return nil, err
+	}
+	return vectors, nil
+}
+
+// Request builds a new unsigned request of the vector.
+func (v *Vector) Request() (*http.Request, error) {
+	req, err := http.NewRequest(v.Method, v.URL, strings.NewReader(v.Body)) //nolint:noctx // test vector
+	if err != nil {
+		return nil, err
+	}
+	if v.Body == "" {
+		req.Body = nil
+		req.GetBody = nil
+	}
+	req.Header = v.Header.Clone()
+	return req, nil
+}
+
+// Compute signs the request of the vector and returns the result as a new vector.
+func (v *Vector) Compute() (*Vector, error) {
+	alg, ok := signing.AlgorithmByName(v.Algorithm)
+	if !ok {
+		return nil, fmt.Errorf("unknown signature algorithm %q", v.Algorithm)
+	}
+	req, err := v.Request()
+	if err != nil {
+		return nil, err
+	}
+	canonicalRequest, err := signing.CanonicalRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	stringToSign, err := signing.StringToSign(alg, canonicalRequest)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signing.SignWithRand(NewDeterministicReader(v.Name), Signer(), alg, stringToSign)
+	if err != nil {
+		return nil, err
+	}
+	got := *v
+	got.CanonicalRequest = canonicalRequest
+	got.StringToSign = stringToSign
+	got.Signature = signature
+	got.Authorization = signing.AuthHeaderValue(alg, PublicKeyID, signing.SignedHeaders(req), signature)
+	return &got, nil
+}
+
+// Check recomputes the vector and reports the first field that differs.
+// A signature mismatch with an otherwise matching vector whose signature still verifies
+// means that crypto/rsa no longer reads the PSS salt from the reader given to signing.SignWithRand.
+func (v *Vector) Check() error {
+	got, err := v.Compute()
+	if err != nil {
+		return err
+	}
+	for _, f := range []struct{ name, want, got string }{
+		{"canonicalRequest", v.CanonicalRequest, got.CanonicalRequest},
+		{"stringToSign", v.StringToSign, got.StringToSign},
+		{"signature", v.Signature, got.Signature},
+		{"authorization", v.Authorization, got.Authorization},
+	} {
+		if f.want == f.got {
+			continue
+		}
+		if f.name == "signature" && signing.Verify(Signer().Public().(*rsa.PublicKey), v.StringToSign, v.Signature) == nil {
+			return fmt.Errorf("%s: signature is valid but not reproduced, the PSS salt is not read from the deterministic reader", v.Name)
+		}
+		return fmt.Errorf("%s: %s mismatch:\nwant %q\ngot  %q", v.Name, f.name, f.want, f.got)
+	}
+	return nil
+}
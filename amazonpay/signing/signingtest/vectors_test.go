@@ -0,0 +1,25 @@
+package signingtest_test
+
+import (
+	"testing"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+func TestVectors(t *testing.T) {
+	vectors, err := signingtest.Vectors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors) == 0 {
+		t.Fatal("no test vectors")
+	}
+	for i := range vectors {
+		v := &vectors[i]
+		t.Run(v.Name, func(t *testing.T) {
+			if err := v.Check(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
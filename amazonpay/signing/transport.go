@@ -0,0 +1,169 @@
+package signing
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+)
+
+// DateFormat is the layout of the x-amz-pay-date header.
+const DateFormat = "20060102T150405Z"
+
+// Transport is an http.RoundTripper adding the x-amz-pay-* headers, an idempotency key for POST requests
+// and the Authorization header to every request.
+// The body is hashed by streaming it from GetBody when the request has one, as those built by
+// http.NewRequest from a bytes.Buffer, bytes.Reader or strings.Reader do.
+// Other bodies are read into memory once to be hashed and sent, see PayloadHash.
+type Transport struct {
+	// Base sends the signed requests, http.DefaultTransport when nil.
+	Base        http.RoundTripper
+	PublicKeyID string
+	Signer      crypto.Signer
+	// Algorithm is AlgorithmV1 when nil.
+	Algorithm SignatureAlgorithm
+	// Region is sent as x-amz-pay-region.
+	Region string
+	// Host is sent as x-amz-pay-host, the request host when empty.
+	Host string
+	// Now is time.Now when nil.
+	Now func() time.Time
+	// IdempotencyKey is used for POST requests without x-amz-pay-idempotency-key, a new xid when nil.
+	IdempotencyKey func() string
+	// SignedHeaders selects the headers signed in addition to RequiredHeaders, every header when nil.
+	SignedHeaders HeaderPolicy
+	// OnSigned is called with the diagnostics of every signed request when set.
+	OnSigned func(req *http.Request, d *Diagnostics)
+}
+
+// SignError is returned by Transport.RoundTrip when the request could not be signed.
+// The request was not sent.
+type SignError struct {
+	Err error
+}
+
+func (e *SignError) Error() string {
+	return "sign request: " + e.Err.Error()
+}
+
+func (e *SignError) Unwrap() error {
+	return e.Err
+}
+
+// RoundTrip signs a clone of req and sends it with Base.
+// Signing failures are returned as *SignError.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	signed, err := t.Sign(req)
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, &SignError{Err: err}
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(signed)
+}
+
+// Sign returns a signed clone of req. req itself is not modified,
+// but a body without GetBody is consumed by the clone.
+func (t *Transport) Sign(req *http.Request) (*http.Request, error) {
+	if isNil(t.Signer) {
+		return nil, errors.New("missing signer")
+	}
+	if req.URL == nil {
+		return nil, errors.New("missing request URL")
+	}
+	alg := t.Algorithm
+	if alg == nil {
+		alg = AlgorithmV1
+	}
+	now := time.Now
+	if t.Now != nil {
+		now = t.Now
+	}
+	host := t.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	for key := range req.Header {
+		if !validHeaderName(key) {
+			return nil, fmt.Errorf("invalid header name %q", key)
+		}
+	}
+
+	r := req.Clone(req.Context())
+	if r.Header == nil {
+		r.Header = http.Header{}
+	}
+	// Send the normalized path, so that the path on the wire is the canonical URI.
+	r.URL.RawPath = CanonicalURI(r)
+	r.URL.Path, _ = url.PathUnescape(r.URL.RawPath)
+	r.Header.Del("Authorization")
+	if r.Method == http.MethodPost && r.Header.Get("x-amz-pay-idempotency-key") == "" {
+		r.Header.Set("x-amz-pay-idempotency-key", t.idempotencyKey())
+	}
+	r.Header.Set("x-amz-pay-region", t.Region)
+	r.Header.Set("x-amz-pay-host", host)
+	r.Header.Set("x-amz-pay-date", now().UTC().Format(DateFormat))
+	if r.Header.Get("content-type") == "" {
+		r.Header.Set("content-type", "application/json")
+	}
+	if r.Header.Get("accept") == "" {
+		r.Header.Set("accept", "application/json")
+	}
+
+	names := SignedHeaderNames(r, t.SignedHeaders)
+	canonicalRequest, err := CanonicalRequestWithHeaders(r, names)
+	if err != nil {
+		return nil, err
+	}
+	stringToSign, err := StringToSign(alg, canonicalRequest)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := SignContext(r.Context(), t.Signer, alg, stringToSign)
+	if err != nil {
+		return nil, err
+	}
+	signedHeaders := strings.Join(names, ";")
+	r.Header.Set("Authorization", AuthHeaderValue(alg, t.PublicKeyID, signedHeaders, signature))
+
+	if t.OnSigned != nil {
+		t.OnSigned(r, NewDiagnostics(canonicalRequest, stringToSign, signedHeaders))
+	}
+	return r, nil
+}
+
+// validHeaderName reports whether name is an RFC 9110 token.
+// Other names would be rejected by net/http and break the SignedHeaders list of the Authorization header.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (t *Transport) idempotencyKey() string {
+	if t.IdempotencyKey != nil {
+		return t.IdempotencyKey()
+	}
+	return xid.New().String()
+}
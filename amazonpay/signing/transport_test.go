@@ -0,0 +1,23 @@
+package signing_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+func TestSignRejectsInvalidHeaderNames(t *testing.T) {
+	transport := &signing.Transport{PublicKeyID: signingtest.PublicKeyID, Signer: signingtest.Signer(), Region: "jp"}
+	for _, name := range []string{",", "x;y", "a b", "x=1", ""} {
+		req, err := http.NewRequest(http.MethodGet, "https://pay-api.amazon.jp/v2/charges/S03-1", nil) //nolint:noctx // test
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header[name] = []string{"v"}
+		if _, err := transport.Sign(req); err == nil {
+			t.Errorf("Sign with header %q succeeded", name)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package signing
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSignature is returned when a signature does not match.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// KeyLookup returns the public key registered for publicKeyID.
+// It returns an error, or a nil key, for an unknown publicKeyID.
+type KeyLookup func(publicKeyID string) (*rsa.PublicKey, error)
+
+// AuthHeader is a parsed Authorization header value built by AuthHeaderValue.
+type AuthHeader struct {
+	Algorithm     SignatureAlgorithm
+	PublicKeyID   string
+	SignedHeaders string
+	Signature     string
+}
+
+// ParseAuthHeaderValue parses an Authorization header value built by AuthHeaderValue.
+func ParseAuthHeaderValue(value string) (*AuthHeader, error) {
+	name, params, ok := strings.Cut(value, " ")
+	if !ok {
+		return nil, errors.New("malformed authorization header")
+	}
+	alg, ok := AlgorithmByName(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown signature algorithm %q", name)
+	}
+	h := &AuthHeader{Algorithm: alg}
+	for _, param := range strings.Split(params, ",") {
+		key, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok {
+			return nil, errors.New("malformed authorization header")
+		}
+		switch key {
+		case "PublicKeyId":
+			h.PublicKeyID = v
+		case "SignedHeaders":
+			h.SignedHeaders = v
+		case "Signature":
+			h.Signature = v
+		}
+	}
+	if h.PublicKeyID == "" || h.SignedHeaders == "" || h.Signature == "" {
+		return nil, errors.New("incomplete authorization header")
+	}
+	return h, nil
+}
+
+// Verify checks the base64 encoded signature of stringToSign.
+// The algorithm is taken from the first line of stringToSign.
+func Verify(publicKey *rsa.PublicKey, stringToSign, signature string) error {
+	if publicKey == nil {
+		return errors.New("missing public key")
+	}
+	name, _, _ := strings.Cut(stringToSign, "\n")
+	alg, ok := AlgorithmByName(name)
+	if !ok {
+		return fmt.Errorf("unknown signature algorithm %q", name)
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return err
+	}
+	hashed := sha256.Sum256([]byte(stringToSign))
+	if err := rsa.VerifyPSS(publicKey, hashFunc, hashed[:], sig, pssOptions(alg)); err != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyOptions are the optional checks of VerifyRequestWithOptions.
+type VerifyOptions struct {
+	// MaxClockSkew rejects requests whose x-amz-pay-date is further than MaxClockSkew from Now when positive.
+	MaxClockSkew time.Duration
+	// Now is time.Now when nil.
+	Now func() time.Time
+}
+
+// VerifyRequest checks the Authorization header of r against the canonical request
+// rebuilt from the headers listed in SignedHeaders.
+// Every header of RequiredHeaders present on r must be listed in SignedHeaders.
+func VerifyRequest(r *http.Request, keyLookup KeyLookup) error {
+	return VerifyRequestWithOptions(r, keyLookup, VerifyOptions{})
+}
+
+// VerifyRequestWithOptions is VerifyRequest with the additional checks of opts.
+func VerifyRequestWithOptions(r *http.Request, keyLookup KeyLookup, opts VerifyOptions) error {
+	h, err := ParseAuthHeaderValue(r.Header.Get("Authorization"))
+	if err != nil {
+		return err
+	}
+	if err := checkRequiredHeadersSigned(r.Header, h.SignedHeaders); err != nil {
+		return err
+	}
+	if opts.MaxClockSkew > 0 {
+		if err := checkDate(r.Header, opts); err != nil {
+			return err
+		}
+	}
+	publicKey, err := keyLookup(h.PublicKeyID)
+	if err != nil {
+		return err
+	}
+	if publicKey == nil {
+		return fmt.Errorf("%w: unknown public key id %s", ErrInvalidSignature, h.PublicKeyID)
+	}
+	canonicalRequest, err := WireCanonicalRequest(r, h.SignedHeaders)
+	if err != nil {
+		return err
+	}
+	stringToSign, err := StringToSign(h.Algorithm, canonicalRequest)
+	if err != nil {
+		return err
+	}
+	return Verify(publicKey, stringToSign, h.Signature)
+}
+
+// checkRequiredHeadersSigned rejects a signature leaving a header of RequiredHeaders present in h unsigned,
+// such as x-amz-pay-date or x-amz-pay-idempotency-key.
+func checkRequiredHeadersSigned(h http.Header, signedHeaders string) error {
+	signed := map[string]bool{}
+	for _, name := range strings.Split(signedHeaders, ";") {
+		signed[name] = true
+	}
+	for _, name := range RequiredHeaders {
+		if !signed[name] && len(headerValues(h, name)) > 0 {
+			return fmt.Errorf("%w: required header %s is not signed", ErrInvalidSignature, name)
+		}
+	}
+	return nil
+}
+
+// checkDate rejects a request without x-amz-pay-date or dated further than opts.MaxClockSkew from now.
+func checkDate(h http.Header, opts VerifyOptions) error {
+	date, err := time.Parse(DateFormat, h.Get("x-amz-pay-date"))
+	if err != nil {
+		return fmt.Errorf("%w: missing or malformed x-amz-pay-date", ErrInvalidSignature)
+	}
+	now := time.Now
+	if opts.Now != nil {
+		now = opts.Now
+	}
+	if skew := now().Sub(date); skew > opts.MaxClockSkew || skew < -opts.MaxClockSkew {
+		return fmt.Errorf("%w: x-amz-pay-date %s is %s away from now", ErrInvalidSignature, date.Format(DateFormat), skew.Abs())
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package signing_test
+
+import (
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+var signedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func lookupTestKey(string) (*rsa.PublicKey, error) {
+	return signingtest.Signer().Public().(*rsa.PublicKey), nil
+}
+
+func signedRequest(t *testing.T, method string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(method, "https://pay-api.amazon.jp/v2/charges", nil) //nolint:noctx // test
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := &signing.Transport{
+		PublicKeyID: signingtest.PublicKeyID,
+		Signer:      signingtest.Signer(),
+		Region:      "jp",
+		Now:         func() time.Time { return signedAt },
+	}
+	signed, err := transport.Sign(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// resign replaces the signature of r with a valid one covering only names.
+func resign(t *testing.T, r *http.Request, names ...string) {
+	t.Helper()
+	canonicalRequest, err := signing.CanonicalRequestWithHeaders(r, names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringToSign, err := signing.StringToSign(signing.AlgorithmV1, canonicalRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := signing.Sign(signingtest.Signer(), signing.AlgorithmV1, stringToSign)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Authorization", signing.AuthHeaderValue(signing.AlgorithmV1, signingtest.PublicKeyID, strings.Join(names, ";"), signature))
+}
+
+func TestVerifyRequest(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		if err := signing.VerifyRequest(signedRequest(t, method), lookupTestKey); err != nil {
+			t.Errorf("%s: %v", method, err)
+		}
+	}
+}
+
+func TestVerifyRequestUnknownKey(t *testing.T) {
+	noKey := func(string) (*rsa.PublicKey, error) { return nil, nil }
+	if err := signing.VerifyRequest(signedRequest(t, http.MethodGet), noKey); !errors.Is(err, signing.ErrInvalidSignature) {
+		t.Errorf("error %v, want ErrInvalidSignature", err)
+	}
+	if err := signing.Verify(nil, signing.AlgorithmV1.Name()+"\n", ""); err == nil {
+		t.Error("verified without a public key")
+	}
+}
+
+func TestVerifyRequestRejectsUnsignedRequiredHeaders(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		method string
+		signed []string
+	}{
+		{"accept only", http.MethodGet, []string{"accept"}},
+		{"no date", http.MethodGet, []string{"accept", "content-type", "user-agent", "x-amz-pay-host", "x-amz-pay-region"}},
+		{"no idempotency key", http.MethodPost, []string{"accept", "content-type", "user-agent", "x-amz-pay-date", "x-amz-pay-host", "x-amz-pay-region"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := signedRequest(t, tt.method)
+			resign(t, r, tt.signed...)
+			if err := signing.VerifyRequest(r, lookupTestKey); !errors.Is(err, signing.ErrInvalidSignature) {
+				t.Errorf("VerifyRequest = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func TestVerifyRequestMaxClockSkew(t *testing.T) {
+	r := signedRequest(t, http.MethodGet)
+	for _, tt := range []struct {
+		now     time.Time
+		wantErr bool
+	}{
+		{signedAt, false},
+		{signedAt.Add(4 * time.Minute), false},
+		{signedAt.Add(-4 * time.Minute), false},
+		{signedAt.Add(6 * time.Minute), true},
+		{signedAt.Add(-6 * time.Minute), true},
+	} {
+		opts := signing.VerifyOptions{MaxClockSkew: 5 * time.Minute, Now: func() time.Time { return tt.now }}
+		err := signing.VerifyRequestWithOptions(r, lookupTestKey, opts)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("now %s: VerifyRequestWithOptions = %v, want error %t", tt.now, err, tt.wantErr)
+		}
+	}
+}
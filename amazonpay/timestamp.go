@@ -0,0 +1,31 @@
+package amazonpay
+
+import "time"
+
+// TimestampFormat is the layout of the timestamps returned by Amazon Pay.
+const TimestampFormat = "20060102T150405Z"
+
+// Timestamp is a UTC timestamp in TimestampFormat.
+// It is kept as received, so marshaling it again returns the exact wire value.
+type Timestamp string
+
+// NewTimestamp formats t in UTC as a Timestamp.
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp(t.UTC().Format(TimestampFormat))
+}
+
+// Time parses the timestamp. Fractional seconds are accepted.
+func (t Timestamp) Time() (time.Time, error) {
+	return time.Parse(TimestampFormat, string(t))
+}
+
+// IsZero reports whether the timestamp is empty.
+func (t Timestamp) IsZero() bool {
+	return t == ""
+}
+
+// Before reports whether the timestamp is before u. An empty or invalid timestamp is never before u.
+func (t Timestamp) Before(u time.Time) bool {
+	tt, err := t.Time()
+	return err == nil && tt.Before(u)
+}
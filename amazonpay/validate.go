@@ -0,0 +1,281 @@
+package amazonpay
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Documented length limits of request fields, in characters.
+const (
+	MaxSoftDescriptorLength      = 16
+	MaxMerchantReferenceIDLength = 256
+	MaxMerchantStoreNameLength   = 50
+	MaxNoteToBuyerLength         = 255
+	MaxCustomInformationLength   = 4096
+	MaxClosureReasonLength       = 255
+)
+
+// ValidationError is a constraint violation of a single request field.
+// Field is the JSON path of the field, such as "paymentDetails.softDescriptor".
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors holds every violation found by Validate.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return "amazonpay: invalid request: " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationErrors) add(field, format string, args ...interface{}) {
+	*e = append(*e, &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns e as an error, nil without violations.
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationErrors) required(field, value string) {
+	if value == "" {
+		e.add(field, "is required")
+	}
+}
+
+func (e *ValidationErrors) maxLength(field, value string, limit int) {
+	if n := utf8.RuneCountInString(value); n > limit {
+		e.add(field, "is %d characters, the limit is %d", n, limit)
+	}
+}
+
+func (e *ValidationErrors) oneOf(field, value string, allowed ...string) {
+	if value == "" {
+		return
+	}
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	e.add(field, "must be one of %s", strings.Join(allowed, ", "))
+}
+
+// price checks that p is a positive amount with the precision of its currency.
+func (e *ValidationErrors) price(field string, p *Price, required bool) {
+	if p == nil {
+		if required {
+			e.add(field, "is required")
+		}
+		return
+	}
+	m, err := MoneyFromPrice(p)
+	if err != nil {
+		e.add(field, "%v", strings.TrimPrefix(err.Error(), "amazonpay: "))
+		return
+	}
+	if m.IsNegative() || m.IsZero() {
+		e.add(field+".amount", "must be greater than zero")
+	}
+}
+
+func (e *ValidationErrors) sameCurrency(field string, a, b *Price) {
+	if a != nil && b != nil && a.CurrencyCode != b.CurrencyCode {
+		e.add(field, "currency %s does not match %s", b.CurrencyCode, a.CurrencyCode)
+	}
+}
+
+func (e *ValidationErrors) merchantMetadata(field string, m *MerchantMetadata) {
+	if m == nil {
+		return
+	}
+	e.maxLength(field+".merchantReferenceId", m.MerchantReferenceID, MaxMerchantReferenceIDLength)
+	e.maxLength(field+".merchantStoreName", m.MerchantStoreName, MaxMerchantStoreNameLength)
+	e.maxLength(field+".noteToBuyer", m.NoteToBuyer, MaxNoteToBuyerLength)
+	e.maxLength(field+".customInformation", m.CustomInformation, MaxCustomInformationLength)
+}
+
+func (e *ValidationErrors) paymentDetails(field string, p *PaymentDetails) {
+	if p == nil {
+		return
+	}
+	e.oneOf(field+".paymentIntent", string(p.PaymentIntent),
+		string(PaymentIntentConfirm), string(PaymentIntentAuthorize), string(PaymentIntentAuthorizeWithCapture))
+	switch p.PaymentIntent {
+	case PaymentIntentAuthorize, PaymentIntentAuthorizeWithCapture:
+		if p.ChargeAmount == nil {
+			e.add(field+".chargeAmount", "is required for paymentIntent %s", p.PaymentIntent)
+		}
+	}
+	e.price(field+".chargeAmount", p.ChargeAmount, false)
+	e.price(field+".totalOrderAmount", p.TotalOrderAmount, false)
+	e.sameCurrency(field+".totalOrderAmount.currencyCode", p.ChargeAmount, p.TotalOrderAmount)
+	e.maxLength(field+".softDescriptor", p.SoftDescriptor, MaxSoftDescriptorLength)
+}
+
+func (e *ValidationErrors) deliverySpecifications(field string, d *DeliverySpecifications) {
+	if d == nil {
+		return
+	}
+	for i, s := range d.SpecialRestrictions {
+		e.oneOf(fmt.Sprintf("%s.specialRestrictions[%d]", field, i), s,
+			SpecialRestrictionRestrictPOBoxes, SpecialRestrictionRestrictPackstations)
+	}
+	a := d.AddressRestrictions
+	if a == nil {
+		return
+	}
+	if a.Type == "" {
+		e.add(field+".addressRestrictions.type", "is required")
+	}
+	e.oneOf(field+".addressRestrictions.type", string(a.Type),
+		string(AddressRestrictionAllowed), string(AddressRestrictionNotAllowed))
+	countries := make([]string, 0, len(a.Restrictions))
+	for country := range a.Restrictions {
+		countries = append(countries, country)
+	}
+	sort.Strings(countries)
+	for _, country := range countries {
+		if len(country) != 2 || strings.ToUpper(country) != country {
+			e.add(field+".addressRestrictions.restrictions."+country, "must be keyed by an upper case ISO 3166-1 alpha-2 country code")
+		}
+	}
+}
+
+// Validate checks the documented constraints of the request.
+func (r *CreateCheckoutSessionRequest) Validate() error {
+	var errs ValidationErrors
+	if r == nil {
+		errs.add("", "request is required")
+		return errs
+	}
+	if r.WebCheckoutDetails == nil {
+		errs.add("webCheckoutDetails.checkoutReviewReturnUrl", "is required")
+	} else {
+		errs.required("webCheckoutDetails.checkoutReviewReturnUrl", r.WebCheckoutDetails.CheckoutReviewReturnURL)
+	}
+	errs.required("storeId", r.StoreID)
+	errs.oneOf("chargePermissionType", string(r.ChargePermissionType),
+		string(ChargePermissionTypeOneTime), string(ChargePermissionTypeRecurring),
+		string(ChargePermissionTypePaymentMethodOnFile))
+	if r.ChargePermissionType == ChargePermissionTypeRecurring {
+		if r.RecurringMetadata == nil {
+			errs.add("recurringMetadata", "is required for a Recurring charge permission")
+		}
+	}
+	if r.RecurringMetadata != nil {
+		if f := r.RecurringMetadata.Frequency; f != nil {
+			errs.oneOf("recurringMetadata.frequency.unit", string(f.Unit),
+				string(FrequencyUnitYear), string(FrequencyUnitMonth), string(FrequencyUnitWeek),
+				string(FrequencyUnitDay), string(FrequencyUnitVariable))
+		}
+		errs.price("recurringMetadata.amount", r.RecurringMetadata.Amount, false)
+	}
+	errs.deliverySpecifications("deliverySpecifications", r.DeliverySpecifications)
+	errs.paymentDetails("paymentDetails", r.PaymentDetails)
+	errs.merchantMetadata("merchantMetadata", r.MerchantMetadata)
+	return errs.err()
+}
+
+// Validate checks the documented constraints of the request.
+func (r *UpdateCheckoutSessionRequest) Validate() error {
+	var errs ValidationErrors
+	if r == nil {
+		errs.add("", "request is required")
+		return errs
+	}
+	errs.paymentDetails("paymentDetails", r.PaymentDetails)
+	errs.merchantMetadata("merchantMetadata", r.MerchantMetadata)
+	return errs.err()
+}
+
+// Validate checks the documented constraints of the request.
+func (r *CompleteCheckoutSessionRequest) Validate() error {
+	var errs ValidationErrors
+	if r == nil {
+		errs.add("", "request is required")
+		return errs
+	}
+	errs.price("chargeAmount", r.ChargeAmount, true)
+	return errs.err()
+}
+
+// Validate checks the documented constraints of the request.
+func (r *CreateChargeRequest) Validate() error {
+	var errs ValidationErrors
+	if r == nil {
+		errs.add("", "request is required")
+		return errs
+	}
+	errs.required("chargePermissionId", r.ChargePermissionID)
+	errs.price("chargeAmount", r.ChargeAmount, true)
+	errs.maxLength("softDescriptor", r.SoftDescriptor, MaxSoftDescriptorLength)
+	errs.merchantMetadata("merchantMetadata", r.MerchantMetadata)
+	return errs.err()
+}
+
+// Validate checks the documented constraints of the request.
+func (r *CaptureChargeRequest) Validate() error {
+	var errs ValidationErrors
+	if r == nil {
+		errs.add("", "request is required")
+		return errs
+	}
+	errs.price("captureAmount", r.CaptureAmount, true)
+	errs.maxLength("softDescriptor", r.SoftDescriptor, MaxSoftDescriptorLength)
+	errs.merchantMetadata("merchantMetadata", r.MerchantMetadata)
+	return errs.err()
+}
+
+// Validate checks the documented constraints of the request.
+func (r *CreateRefundRequest) Validate() error {
+	var errs ValidationErrors
+	if r == nil {
+		errs.add("", "request is required")
+		return errs
+	}
+	errs.required("chargeId", r.ChargeID)
+	errs.price("refundAmount", r.RefundAmount, true)
+	errs.maxLength("softDescriptor", r.SoftDescriptor, MaxSoftDescriptorLength)
+	return errs.err()
+}
+
+// Validate checks the documented constraints of the request.
+func (r *CloseChargePermissionRequest) Validate() error {
+	var errs ValidationErrors
+	if r == nil {
+		errs.add("", "request is required")
+		return errs
+	}
+	errs.maxLength("closureReason", r.ClosureReason, MaxClosureReasonLength)
+	return errs.err()
+}
+
+type validator interface {
+	Validate() error
+}
+
+// validate runs Validate on req when ValidateRequests is set.
+func (c *Client) validate(req validator) error {
+	if !c.ValidateRequests {
+		return nil
+	}
+	return req.Validate()
+}
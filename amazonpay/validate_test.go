@@ -0,0 +1,183 @@
+package amazonpay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/signingtest"
+)
+
+func validCheckoutSession() *CreateCheckoutSessionRequest {
+	return &CreateCheckoutSessionRequest{
+		WebCheckoutDetails: &WebCheckoutDetails{CheckoutReviewReturnURL: "https://example.com/review"},
+		StoreID:            "amzn1.application-oa2-client.xxxxx",
+	}
+}
+
+// invalidFields returns the fields of the violations of err, nil without.
+func invalidFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error %v is not ValidationErrors", err)
+	}
+	fields := make([]string, 0, len(errs))
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	return fields
+}
+
+func TestCreateCheckoutSessionRequestValidate(t *testing.T) {
+	jpy := func(amount string) *Price { return &Price{Amount: amount, CurrencyCode: "JPY"} }
+	for _, tt := range []struct {
+		name   string
+		modify func(r *CreateCheckoutSessionRequest)
+		want   []string
+	}{
+		{"valid", func(*CreateCheckoutSessionRequest) {}, nil},
+		{"missing checkoutReviewReturnUrl", func(r *CreateCheckoutSessionRequest) {
+			r.WebCheckoutDetails.CheckoutReviewReturnURL = ""
+		}, []string{"webCheckoutDetails.checkoutReviewReturnUrl"}},
+		{"missing webCheckoutDetails", func(r *CreateCheckoutSessionRequest) {
+			r.WebCheckoutDetails = nil
+		}, []string{"webCheckoutDetails.checkoutReviewReturnUrl"}},
+		{"softDescriptor of 16", func(r *CreateCheckoutSessionRequest) {
+			r.PaymentDetails = &PaymentDetails{SoftDescriptor: strings.Repeat("あ", 16)}
+		}, nil},
+		{"softDescriptor over 16", func(r *CreateCheckoutSessionRequest) {
+			r.PaymentDetails = &PaymentDetails{SoftDescriptor: strings.Repeat("a", 17)}
+		}, []string{"paymentDetails.softDescriptor"}},
+		{"noteToBuyer of 255", func(r *CreateCheckoutSessionRequest) {
+			r.MerchantMetadata = &MerchantMetadata{NoteToBuyer: strings.Repeat("あ", 255)}
+		}, nil},
+		{"noteToBuyer over 255", func(r *CreateCheckoutSessionRequest) {
+			r.MerchantMetadata = &MerchantMetadata{NoteToBuyer: strings.Repeat("a", 256)}
+		}, []string{"merchantMetadata.noteToBuyer"}},
+		{"currency mismatch", func(r *CreateCheckoutSessionRequest) {
+			r.PaymentDetails = &PaymentDetails{
+				PaymentIntent:    PaymentIntentAuthorize,
+				ChargeAmount:     jpy("100"),
+				TotalOrderAmount: &Price{Amount: "1.00", CurrencyCode: "USD"},
+			}
+		}, []string{"paymentDetails.totalOrderAmount.currencyCode"}},
+		{"Authorize without chargeAmount", func(r *CreateCheckoutSessionRequest) {
+			r.PaymentDetails = &PaymentDetails{PaymentIntent: PaymentIntentAuthorize}
+		}, []string{"paymentDetails.chargeAmount"}},
+		{"AuthorizeWithCapture without chargeAmount", func(r *CreateCheckoutSessionRequest) {
+			r.PaymentDetails = &PaymentDetails{PaymentIntent: PaymentIntentAuthorizeWithCapture}
+		}, []string{"paymentDetails.chargeAmount"}},
+		{"AuthorizeWithCapture", func(r *CreateCheckoutSessionRequest) {
+			r.PaymentDetails = &PaymentDetails{PaymentIntent: PaymentIntentAuthorizeWithCapture, ChargeAmount: jpy("100")}
+		}, nil},
+		{"Confirm without chargeAmount", func(r *CreateCheckoutSessionRequest) {
+			r.PaymentDetails = &PaymentDetails{PaymentIntent: PaymentIntentConfirm}
+		}, nil},
+		{"chargeAmount precision", func(r *CreateCheckoutSessionRequest) {
+			r.PaymentDetails = &PaymentDetails{PaymentIntent: PaymentIntentAuthorize, ChargeAmount: jpy("100.5")}
+		}, []string{"paymentDetails.chargeAmount"}},
+		{"Recurring without recurringMetadata", func(r *CreateCheckoutSessionRequest) {
+			r.ChargePermissionType = ChargePermissionTypeRecurring
+		}, []string{"recurringMetadata"}},
+		{"Recurring", func(r *CreateCheckoutSessionRequest) {
+			r.ChargePermissionType = ChargePermissionTypeRecurring
+			r.RecurringMetadata = &RecurringMetadata{Frequency: &Frequency{Unit: FrequencyUnitMonth, Value: "1"}}
+		}, nil},
+		{"specialRestrictions", func(r *CreateCheckoutSessionRequest) {
+			r.DeliverySpecifications = &DeliverySpecifications{
+				SpecialRestrictions: []string{SpecialRestrictionRestrictPOBoxes, "RestrictPackstations"},
+			}
+		}, nil},
+		{"unknown specialRestrictions", func(r *CreateCheckoutSessionRequest) {
+			r.DeliverySpecifications = &DeliverySpecifications{SpecialRestrictions: []string{"RestrictPOBoxes", "POBoxes"}}
+		}, []string{"deliverySpecifications.specialRestrictions[1]"}},
+		{"PaymentMethodOnFile", func(r *CreateCheckoutSessionRequest) {
+			r.ChargePermissionType = ChargePermissionTypePaymentMethodOnFile
+		}, nil},
+		{"unknown chargePermissionType", func(r *CreateCheckoutSessionRequest) {
+			r.ChargePermissionType = "Subscription"
+		}, []string{"chargePermissionType"}},
+		{"unknown paymentIntent", func(r *CreateCheckoutSessionRequest) {
+			r.PaymentDetails = &PaymentDetails{PaymentIntent: "Charge"}
+		}, []string{"paymentDetails.paymentIntent"}},
+		{"every violation", func(r *CreateCheckoutSessionRequest) {
+			r.WebCheckoutDetails = nil
+			r.StoreID = ""
+			r.ChargePermissionType = ChargePermissionTypeRecurring
+			r.MerchantMetadata = &MerchantMetadata{NoteToBuyer: strings.Repeat("a", 256)}
+		}, []string{"webCheckoutDetails.checkoutReviewReturnUrl", "storeId", "recurringMetadata", "merchantMetadata.noteToBuyer"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := validCheckoutSession()
+			tt.modify(r)
+			if got := invalidFields(t, r.Validate()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("invalid fields %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestValidate(t *testing.T) {
+	jpy := &Price{Amount: "100", CurrencyCode: "JPY"}
+	for _, tt := range []struct {
+		name string
+		req  validator
+		want []string
+	}{
+		{"charge", &CreateChargeRequest{ChargePermissionID: "B01", ChargeAmount: jpy}, nil},
+		{"charge without amount", &CreateChargeRequest{ChargePermissionID: "B01"}, []string{"chargeAmount"}},
+		{"charge softDescriptor", &CreateChargeRequest{ChargePermissionID: "B01", ChargeAmount: jpy, SoftDescriptor: strings.Repeat("a", 17)}, []string{"softDescriptor"}},
+		{"charge zero amount", &CreateChargeRequest{ChargePermissionID: "B01", ChargeAmount: &Price{Amount: "0", CurrencyCode: "JPY"}}, []string{"chargeAmount.amount"}},
+		{"capture", &CaptureChargeRequest{CaptureAmount: jpy}, nil},
+		{"capture without amount", &CaptureChargeRequest{}, []string{"captureAmount"}},
+		{"refund", &CreateRefundRequest{ChargeID: "S01", RefundAmount: jpy}, nil},
+		{"refund without charge", &CreateRefundRequest{RefundAmount: jpy}, []string{"chargeId"}},
+		{"complete", &CompleteCheckoutSessionRequest{ChargeAmount: jpy}, nil},
+		{"complete unknown currency", &CompleteCheckoutSessionRequest{ChargeAmount: &Price{Amount: "1", CurrencyCode: "XXX"}}, []string{"chargeAmount"}},
+		{"update", &UpdateCheckoutSessionRequest{PaymentDetails: &PaymentDetails{PaymentIntent: PaymentIntentAuthorize}}, []string{"paymentDetails.chargeAmount"}},
+		{"close", &CloseChargePermissionRequest{ClosureReason: strings.Repeat("a", 256)}, []string{"closureReason"}},
+		{"nil", (*CreateChargeRequest)(nil), []string{""}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := invalidFields(t, tt.req.Validate()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("invalid fields %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRequestsIsNotSent(t *testing.T) {
+	sent := 0
+	rt := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		sent++
+		return &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	})
+	c := newTestClient(t, signingtest.Signer(), rt)
+	invalid := &CreateChargeRequest{ChargePermissionID: "B01"}
+
+	c.ValidateRequests = true
+	_, httpResp, err := c.CreateCharge(context.Background(), invalid)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || httpResp != nil {
+		t.Fatalf("CreateCharge = %v, %v, want ValidationErrors and no response", httpResp, err)
+	}
+	if sent != 0 {
+		t.Fatalf("an invalid request was sent")
+	}
+
+	c.ValidateRequests = false
+	if _, _, err := c.CreateCharge(context.Background(), invalid); err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 {
+		t.Errorf("%d requests sent without ValidateRequests, want 1", sent)
+	}
+}
@@ -0,0 +1,211 @@
+// Command amazonpay-keys generates and manages Amazon Pay RSA key pairs.
+//
+//	amazonpay-keys generate -out private.pem > public.pem
+//	amazonpay-keys public -key private.pem
+//	amazonpay-keys fingerprint -key private.pem
+//	amazonpay-keys convert -key private.pem -format base64
+//	amazonpay-keys match -key private.pem -public public.pem
+//
+// Upload the public key in Seller Central and keep the private key readable by the services only.
+// Keys are read in any format accepted by package keys, "-" reads stdin.
+// AMAZON_PAY_PRIVATE_KEY_PASSPHRASE decrypts an encrypted key.
+package main
+
+import (
+	"crypto/rsa"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/keys"
+)
+
+const usage = `usage: amazonpay-keys <command> [flags]
+
+commands:
+  generate     generate a PKCS#8 private key and print its public key PEM
+  public       print the public key PEM of a private key
+  fingerprint  print the SHA-256 fingerprint of a private or public key
+  convert      convert a private key to pkcs1, pkcs8 or base64
+  match        check that a private key matches a public key
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	commands := map[string]func(args []string) error{
+		"generate":    generate,
+		"public":      public,
+		"fingerprint": fingerprint,
+		"convert":     convert,
+		"match":       match,
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err := cmd(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "amazonpay-keys:", err)
+		os.Exit(1)
+	}
+}
+
+func generate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	bits := fs.Int("bits", keys.MinKeyBits, "RSA key size")
+	out := fs.String("out", "", "private key file, created with mode 0600")
+	_ = fs.Parse(args)
+	if *out == "" {
+		return errors.New("-out is required")
+	}
+
+	key, err := keys.Generate(*bits)
+	if err != nil {
+		return err
+	}
+	priv, err := keys.EncodePKCS8(key)
+	if err != nil {
+		return err
+	}
+	pub, err := keys.EncodePublicKey(&key.PublicKey)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(*out, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(priv); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(pub)
+	return err
+}
+
+func public(args []string) error {
+	fs := flag.NewFlagSet("public", flag.ExitOnError)
+	keyPath := fs.String("key", "", `private key file, "-" for stdin`)
+	_ = fs.Parse(args)
+
+	key, err := loadPrivateKey(*keyPath)
+	if err != nil {
+		return err
+	}
+	pub, err := keys.EncodePublicKey(&key.PublicKey)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(pub)
+	return err
+}
+
+func fingerprint(args []string) error {
+	fs := flag.NewFlagSet("fingerprint", flag.ExitOnError)
+	keyPath := fs.String("key", "", `private or public key file, "-" for stdin`)
+	_ = fs.Parse(args)
+
+	data, err := readKey(*keyPath)
+	if err != nil {
+		return err
+	}
+	pub, err := keys.ParsePublicKey(data)
+	if err != nil {
+		key, perr := keys.Parse(data, passphrase())
+		if perr != nil {
+			return perr
+		}
+		pub = &key.PublicKey
+	}
+	fp, err := keys.Fingerprint(pub)
+	if err != nil {
+		return err
+	}
+	fmt.Println(fp)
+	return nil
+}
+
+func convert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	keyPath := fs.String("key", "", `private key file, "-" for stdin`)
+	format := fs.String("format", "pkcs8", "output format: pkcs1, pkcs8 or base64")
+	_ = fs.Parse(args)
+
+	key, err := loadPrivateKey(*keyPath)
+	if err != nil {
+		return err
+	}
+	var out []byte
+	switch *format {
+	case "pkcs1":
+		out = keys.EncodePKCS1(key)
+	case "pkcs8":
+		out, err = keys.EncodePKCS8(key)
+	case "base64":
+		out, err = keys.EncodeBase64(key)
+		out = append(out, '\n')
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(out)
+	return err
+}
+
+func match(args []string) error {
+	fs := flag.NewFlagSet("match", flag.ExitOnError)
+	keyPath := fs.String("key", "", `private key file, "-" for stdin`)
+	publicPath := fs.String("public", "", "public key file")
+	_ = fs.Parse(args)
+
+	key, err := loadPrivateKey(*keyPath)
+	if err != nil {
+		return err
+	}
+	data, err := readKey(*publicPath)
+	if err != nil {
+		return err
+	}
+	pub, err := keys.ParsePublicKey(data)
+	if err != nil {
+		return err
+	}
+	if !key.PublicKey.Equal(pub) {
+		return errors.New("private key does not match the public key")
+	}
+	fmt.Println("ok")
+	return nil
+}
+
+func loadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := readKey(path)
+	if err != nil {
+		return nil, err
+	}
+	return keys.Parse(data, passphrase())
+}
+
+func readKey(path string) ([]byte, error) {
+	switch path {
+	case "":
+		return nil, errors.New("missing key file")
+	case "-":
+		return io.ReadAll(os.Stdin)
+	default:
+		return os.ReadFile(path)
+	}
+}
+
+func passphrase() []byte {
+	return []byte(os.Getenv("AMAZON_PAY_PRIVATE_KEY_PASSPHRASE"))
+}
@@ -0,0 +1,156 @@
+// Command amazonpay-signer holds the Amazon Pay private key and signs strings to sign for other processes.
+//
+//	AMAZON_PAY_SIGNER_TOKEN=... amazonpay-signer -key ./private.pem -listen unix:/run/amazonpay-signer.sock
+//
+// The socket is created with mode 0600, by default in $XDG_RUNTIME_DIR, or else in a private directory
+// of the temporary directory. An existing file at the socket path is only replaced when it is a socket.
+// TCP addresses are limited to loopback, such as 127.0.0.1:8900, as the API is plain HTTP.
+// AMAZON_PAY_PRIVATE_KEY_PASSPHRASE decrypts an encrypted key.
+// Clients connect with remote.NewSigner and pass it to amazonpay.NewWithSigner.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/keys"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/remote"
+)
+
+func main() {
+	keyPath := flag.String("key", os.Getenv("AMAZON_PAY_PRIVATE_KEY_PATH"), "path to the private key, see package keys for the accepted formats")
+	listen := flag.String("listen", "unix:"+defaultSocket(), `"unix:/path/to/socket" or "127.0.0.1:port"`)
+	auditPath := flag.String("audit", "", "audit log file, stderr if empty")
+	flag.Parse()
+
+	token := os.Getenv("AMAZON_PAY_SIGNER_TOKEN")
+	if token == "" {
+		log.Fatalln("AMAZON_PAY_SIGNER_TOKEN is required")
+	}
+	signer, err := keys.LoadFile(*keyPath, []byte(os.Getenv("AMAZON_PAY_PRIVATE_KEY_PASSPHRASE")))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	var audit io.Writer = os.Stderr
+	if *auditPath != "" {
+		f, err := os.OpenFile(*auditPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		defer f.Close()
+		audit = f
+	}
+
+	ln, err := listener(*listen)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	server := &http.Server{
+		Handler: &remote.Handler{
+			Signer: signer,
+			Token:  token,
+			Audit:  audit,
+		},
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sig
+		_ = server.Close()
+	}()
+
+	log.Println("listening on", *listen)
+	if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Println(err)
+	}
+}
+
+// defaultSocket returns the socket path in the per-user runtime directory,
+// or in a private directory of the temporary directory, created by listener.
+func defaultSocket() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "amazonpay-signer.sock")
+	}
+	return filepath.Join(privateTempDir(), "amazonpay-signer.sock")
+}
+
+func privateTempDir() string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("amazonpay-signer-%d", os.Getuid()))
+}
+
+// listener listens on a unix socket or a loopback TCP address.
+// The token and signatures travel in plain HTTP, so other TCP addresses are rejected.
+func listener(addr string) (net.Listener, error) {
+	path, ok := strings.CutPrefix(addr, "unix:")
+	if !ok {
+		return loopbackListener(addr)
+	}
+	if dir := filepath.Dir(path); dir == privateTempDir() {
+		if err := privateDir(dir); err != nil {
+			return nil, err
+		}
+	}
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+	ln, err := listenUnix(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// removeStaleSocket removes the socket left at path by a previous run. Any other file is kept.
+func removeStaleSocket(path string) error {
+	fi, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+	return os.Remove(path)
+}
+
+func loopbackListener(addr string) (net.Listener, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if !remote.IsLoopbackHost(host) {
+		return nil, fmt.Errorf("%s is not a loopback address, listen on unix:/path or 127.0.0.1:port", addr)
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	// localhost may resolve to a non-loopback address.
+	if tcpAddr, ok := ln.Addr().(*net.TCPAddr); !ok || !tcpAddr.IP.IsLoopback() {
+		ln.Close()
+		return nil, fmt.Errorf("%s resolved to non-loopback %s", addr, ln.Addr())
+	}
+	return ln, nil
+}
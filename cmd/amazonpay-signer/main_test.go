@@ -0,0 +1,68 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListenerKeepsRegularFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signer.sock")
+	if err := os.WriteFile(path, []byte("keep"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if ln, err := listener("unix:" + path); err == nil {
+		ln.Close()
+		t.Fatal("listener replaced a regular file")
+	}
+	if b, err := os.ReadFile(path); err != nil || string(b) != "keep" {
+		t.Errorf("file changed: %q, %v", b, err)
+	}
+}
+
+func TestListenerReplacesStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signer.sock")
+	for i := 0; i < 2; i++ {
+		ln, err := listener("unix:" + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fi, err := os.Lstat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := fi.Mode().Perm(); perm != 0o600 {
+			t.Errorf("socket mode %o, want 600", perm)
+		}
+		// Keep the socket file behind, like a daemon that was killed.
+		if l, ok := ln.(interface{ SetUnlinkOnClose(bool) }); ok {
+			l.SetUnlinkOnClose(false)
+		}
+		ln.Close()
+	}
+}
+
+func TestPrivateDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "private")
+	if err := privateDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := privateDir(dir); err != nil {
+		t.Errorf("existing private dir: %v", err)
+	}
+	if err := os.Chmod(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := privateDir(dir); err == nil {
+		t.Error("accepted a directory readable by others")
+	}
+	link := filepath.Join(t.TempDir(), "link")
+	if err := os.Symlink(t.TempDir(), link); err != nil {
+		t.Fatal(err)
+	}
+	if err := privateDir(link); err == nil {
+		t.Error("accepted a symlink")
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/keys"
 )
 
 // envs.
@@ -24,12 +25,12 @@ var (
 const htmlDir = "./example/recurring"
 
 func main() { //nolint:gocognit // for example
-	privateKeyData, err := os.ReadFile(privateKeyPath)
+	privateKey, err := keys.LoadFile(privateKeyPath, nil)
 	if err != nil {
 		panic(err)
 	}
 
-	amazonpayCli, err := amazonpay.New(publicKeyID, privateKeyData, "jp", true, http.DefaultClient)
+	amazonpayCli, err := amazonpay.NewWithSigner(publicKeyID, privateKey, "jp", true, http.DefaultClient)
 	if err != nil {
 		panic(err)
 	}
@@ -107,7 +108,7 @@ func main() { //nolint:gocognit // for example
 				CheckoutResultReturnURL: fmt.Sprintf("http://localhost:8000/confirm?prescriptionID=%s", prescriptionID),
 			},
 			PaymentDetails: &amazonpay.PaymentDetails{
-				PaymentIntent:                 "AuthorizeWithCapture",
+				PaymentIntent:                 amazonpay.PaymentIntentAuthorizeWithCapture,
 				CanHandlePendingAuthorization: amazonpay.Bool(false),
 				ChargeAmount: &amazonpay.Price{
 					Amount:       "1000",
@@ -148,15 +149,15 @@ func main() { //nolint:gocognit // for example
 		switch httpResp.StatusCode {
 		case http.StatusOK, http.StatusCreated:
 			log.Println("confirm: " + resp.StatusDetails.State)
-			switch resp.StatusDetails.State {
-			case "Open":
-			case "Completed":
+			switch resp.StatusDetails.CheckoutSessionState() {
+			case amazonpay.CheckoutSessionStateOpen:
+			case amazonpay.CheckoutSessionStateCompleted:
 				// TODO should save to database
 				log.Println("chargeID:", resp.ChargeID)
 				log.Println("chargePermissionID:", resp.ChargePermissionID)
 				log.Println("MerchantMetadata:", resp.MerchantMetadata)
 				log.Println("prescriptionID:", prescriptionID)
-			case "Canceled":
+			case amazonpay.CheckoutSessionStateCanceled:
 			}
 			data := struct{}{}
 			if err := template.Must(template.ParseFiles(filepath.Join(htmlDir, "confirm.html"))).Execute(w, data); err != nil {
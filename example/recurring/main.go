@@ -1,7 +1,6 @@
 package main //nolint:cyclop // for example
 
 import (
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -13,12 +12,12 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay"
+	"github.com/sasada-t/amazon-pay-sdk-go/amazonpay/signing/keys"
 )
 
 // envs.
 var (
 	publicKeyID = os.Getenv("AMAZON_PAY_PUBLIC_KEY_ID")
-	privateKey  = os.Getenv("AMAZON_PAY_PRIVATE_KEY")
 	storeID     = os.Getenv("AMAZON_PAY_STORE_ID")
 	merchantID  = os.Getenv("AMAZON_PAY_MERCHANT_ID")
 )
@@ -33,11 +32,11 @@ const htmlDir = "./example/recurring"
 var refID = uuid.New().String()
 
 func main() { //nolint:gocognit,cyclop // for example
-	decodedPrivateKey, err := base64.StdEncoding.DecodeString(privateKey)
+	privateKey, err := keys.LoadEnv("AMAZON_PAY_PRIVATE_KEY", nil)
 	if err != nil {
 		panic(err)
 	}
-	amazonpayCli, err := amazonpay.New(publicKeyID, decodedPrivateKey, "jp", true, http.DefaultClient)
+	amazonpayCli, err := amazonpay.NewWithSigner(publicKeyID, privateKey, "jp", true, http.DefaultClient)
 	if err != nil {
 		panic(err)
 	}
@@ -48,10 +47,10 @@ func main() { //nolint:gocognit,cyclop // for example
 				CheckoutReviewReturnURL: "http://localhost:8000/approve",
 			},
 			StoreID:              storeID,
-			ChargePermissionType: "Recurring",
+			ChargePermissionType: amazonpay.ChargePermissionTypeRecurring,
 			RecurringMetadata: &amazonpay.RecurringMetadata{
 				Frequency: &amazonpay.Frequency{
-					Unit:  "Variable",
+					Unit:  amazonpay.FrequencyUnitVariable,
 					Value: "0",
 				},
 				Amount: &amazonpay.Price{
@@ -60,7 +59,7 @@ func main() { //nolint:gocognit,cyclop // for example
 				},
 			},
 			PaymentDetails: &amazonpay.PaymentDetails{
-				PaymentIntent:                 "Confirm",
+				PaymentIntent:                 amazonpay.PaymentIntentConfirm,
 				CanHandlePendingAuthorization: amazonpay.Bool(false),
 				ChargeAmount: &amazonpay.Price{
 					Amount:       "1",
@@ -109,7 +108,7 @@ func main() { //nolint:gocognit,cyclop // for example
 				CheckoutResultReturnURL: "http://localhost:8000/completed",
 			},
 			PaymentDetails: &amazonpay.PaymentDetails{
-				PaymentIntent:                 "Confirm",
+				PaymentIntent:                 amazonpay.PaymentIntentConfirm,
 				CanHandlePendingAuthorization: amazonpay.Bool(false),
 				ChargeAmount: &amazonpay.Price{
 					Amount:       "1",
@@ -150,9 +149,9 @@ func main() { //nolint:gocognit,cyclop // for example
 		switch httpResp.StatusCode {
 		case http.StatusOK, http.StatusCreated:
 			log.Println("confirm: " + resp.StatusDetails.State)
-			switch resp.StatusDetails.State {
-			case "Open":
-			case "Completed":
+			switch resp.StatusDetails.CheckoutSessionState() {
+			case amazonpay.CheckoutSessionStateOpen:
+			case amazonpay.CheckoutSessionStateCompleted:
 				// TODO should save to database
 				log.Println("ChargeID:", resp.ChargeID)
 				log.Println("ChargePermissionID:", resp.ChargePermissionID)
@@ -161,7 +160,7 @@ func main() { //nolint:gocognit,cyclop // for example
 				b, _ := json.Marshal(resp)
 				log.Println(string(b))
 				chargePermissionID = resp.ChargePermissionID
-			case "Canceled":
+			case amazonpay.CheckoutSessionStateCanceled:
 			}
 			data := struct{}{}
 			if err := template.Must(template.ParseFiles(filepath.Join(htmlDir, "confirm.html"))).Execute(w, data); err != nil {
@@ -183,8 +182,8 @@ func main() { //nolint:gocognit,cyclop // for example
 		switch httpResp.StatusCode {
 		case http.StatusOK, http.StatusCreated:
 			log.Println("recurring: " + cpResp.StatusDetails.State)
-			switch cpResp.StatusDetails.State {
-			case "Chargeable":
+			switch cpResp.StatusDetails.ChargePermissionState() {
+			case amazonpay.ChargePermissionStateChargeable:
 				cResp, httpResp, err := amazonpayCli.CreateCharge(r.Context(), &amazonpay.CreateChargeRequest{
 					ChargePermissionID: chargePermissionID,
 					ChargeAmount: &amazonpay.Price{
@@ -222,8 +221,8 @@ func main() { //nolint:gocognit,cyclop // for example
 				default:
 					http.Error(w, cResp.ErrorResponse.ReasonCode+" | "+cResp.ErrorResponse.Message, http.StatusInternalServerError)
 				}
-			case "NonChargeable":
-			case "Closed":
+			case amazonpay.ChargePermissionStateNonChargeable:
+			case amazonpay.ChargePermissionStateClosed:
 			}
 		default:
 			http.Error(w, cpResp.ErrorResponse.ReasonCode+" | "+cpResp.ErrorResponse.Message, http.StatusInternalServerError)